@@ -0,0 +1,515 @@
+package datetime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronDSTPolicy defines when the fire times of a CronSchedule on wall clock
+// times skipped or repeated by DST transitions fire.
+type CronDSTPolicy int
+
+const (
+	// CronDSTRunOnce fires the times skipped by a DST transition, such as
+	// 02:30 when the clocks go from 02:00 to 03:00, once at the transition,
+	// and the repeated times once, at their first occurrence. So daily jobs
+	// run every day.
+	CronDSTRunOnce CronDSTPolicy = iota
+	// CronDSTSkip doesn't fire the times skipped by a DST transition, and
+	// fires the repeated times once, at their first occurrence.
+	CronDSTSkip
+)
+
+// CronOptions configures ParseCron.
+type CronOptions struct {
+	// Location of the wall clock the expression is evaluated in, UTC if nil.
+	// A "CRON_TZ=" or "TZ=" prefix of the expression takes precedence.
+	Location *time.Location
+	// DSTPolicy defines the fire times on DST transitions
+	DSTPolicy CronDSTPolicy
+}
+
+// CronSchedule is a parsed cron expression, see ParseCron.
+type CronSchedule struct {
+	expr string
+	loc  *time.Location
+	dst  CronDSTPolicy
+
+	second, minute, hour, month uint64
+	dom                         uint64 // days of the month, bits 1 to 31
+	domLast                     []int  // L and L-n, days before the last day of the month
+	domLastWeekday              bool   // LW
+	domNearestWeekday           []int  // nW
+	dow                         uint64 // weekdays, bits 0 to 6
+	dowLast                     uint64 // nL, the last weekdays of the month
+	dowNth                      [][2]int
+	domStar, dowStar            bool
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+var (
+	cronMonthNames   = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+	cronWeekdayNames = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+)
+
+// ParseCron parses a cron expression, with 5 fields, "minute hour
+// day-of-month month day-of-week", or 6 fields with seconds first, or a
+// descriptor, "@yearly", "@monthly", "@weekly", "@daily" or "@hourly".
+//
+// The fields are lists of values, ranges and steps, such as "1,15",
+// "MON-FRI", "*/15" or "9-17/2", with the names of months and weekdays, and
+// Sunday as 0 or 7. The day of the month also takes "L" for the last day,
+// "L-3" for 3 days before it, "LW" for the last weekday and "15W" for the
+// weekday nearest the 15th. The day of the week also takes "5L" for the last
+// Friday and "5#3" for the third Friday of the month. "?" is the same as "*".
+// Like cron, if both the day of the month and of the week are restricted, and
+// neither starts with "*", the days matching either fire.
+//
+// The expression may start with "CRON_TZ=Europe/Stockholm " to evaluate it in
+// that zone instead of opts.Location.
+func ParseCron(expr string, opts CronOptions) (*CronSchedule, error) {
+	s := &CronSchedule{expr: expr, loc: opts.Location, dst: opts.DSTPolicy}
+	if s.loc == nil {
+		s.loc = time.UTC
+	}
+
+	spec := strings.TrimSpace(expr)
+	for _, prefix := range []string{"CRON_TZ=", "TZ="} {
+		if rest, ok := strings.CutPrefix(spec, prefix); ok {
+			zone, rest, _ := strings.Cut(rest, " ")
+			loc, err := LoadLocation(zone)
+			if err != nil {
+				return nil, fmt.Errorf("%w: unknown time zone %q in cron expression %q", ErrInvalidValue, zone, expr)
+			}
+			s.loc, spec = loc, strings.TrimSpace(rest)
+			break
+		}
+	}
+	if descriptor, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = descriptor
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("%w: cron expression %q must have 5 or 6 fields", ErrInvalidValue, expr)
+	}
+
+	var err error
+	fail := func(field string, err error) (*CronSchedule, error) {
+		return nil, fmt.Errorf("%w: invalid %s %q in cron expression %q: %v", ErrInvalidValue, field, fields[0], expr, err)
+	}
+	if s.second, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return fail("second", err)
+	}
+	fields = fields[1:]
+	if s.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return fail("minute", err)
+	}
+	fields = fields[1:]
+	if s.hour, err = parseCronField(fields[0], 0, 23, nil); err != nil {
+		return fail("hour", err)
+	}
+	fields = fields[1:]
+	if err = s.parseDayOfMonth(fields[0]); err != nil {
+		return fail("day of month", err)
+	}
+	fields = fields[1:]
+	if s.month, err = parseCronField(fields[0], 1, 12, cronMonthNames); err != nil {
+		return fail("month", err)
+	}
+	fields = fields[1:]
+	if err = s.parseDayOfWeek(fields[0]); err != nil {
+		return fail("day of week", err)
+	}
+	return s, nil
+}
+
+// MustParseCron is like ParseCron but panics on an error, for expressions
+// that are constants.
+func MustParseCron(expr string, opts CronOptions) *CronSchedule {
+	s, err := ParseCron(expr, opts)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// String returns the expression the schedule was parsed from.
+func (s *CronSchedule) String() string {
+	return s.expr
+}
+
+// Location returns the location the schedule is evaluated in.
+func (s *CronSchedule) Location() *time.Location {
+	return s.loc
+}
+
+func (s *CronSchedule) parseDayOfMonth(field string) error {
+	s.domStar = strings.HasPrefix(field, "*") || field == "?"
+	for _, item := range strings.Split(field, ",") {
+		switch {
+		case item == "L":
+			s.domLast = append(s.domLast, 0)
+		case item == "LW":
+			s.domLastWeekday = true
+		case strings.HasPrefix(item, "L-"):
+			n, err := strconv.Atoi(item[2:])
+			if err != nil || n < 1 || n > 30 {
+				return fmt.Errorf("invalid %q", item)
+			}
+			s.domLast = append(s.domLast, n)
+		case len(item) > 1 && strings.HasSuffix(item, "W"):
+			n, err := strconv.Atoi(item[:len(item)-1])
+			if err != nil || n < 1 || n > 31 {
+				return fmt.Errorf("invalid %q", item)
+			}
+			s.domNearestWeekday = append(s.domNearestWeekday, n)
+		default:
+			days, err := parseCronField(item, 1, 31, nil)
+			if err != nil {
+				return err
+			}
+			s.dom |= days
+		}
+	}
+	return nil
+}
+
+func (s *CronSchedule) parseDayOfWeek(field string) error {
+	s.dowStar = strings.HasPrefix(field, "*") || field == "?"
+	for _, item := range strings.Split(field, ",") {
+		switch {
+		case strings.Contains(item, "#"):
+			day, nth, _ := strings.Cut(item, "#")
+			weekday, err := parseCronValue(day, 0, 7, cronWeekdayNames)
+			if err != nil {
+				return err
+			}
+			n, err := strconv.Atoi(nth)
+			if err != nil || n < 1 || n > 5 {
+				return fmt.Errorf("invalid %q", item)
+			}
+			s.dowNth = append(s.dowNth, [2]int{weekday % 7, n})
+		case len(item) > 1 && strings.HasSuffix(item, "L"):
+			weekday, err := parseCronValue(item[:len(item)-1], 0, 7, cronWeekdayNames)
+			if err != nil {
+				return err
+			}
+			s.dowLast |= 1 << (weekday % 7)
+		default:
+			days, err := parseCronField(item, 0, 7, cronWeekdayNames)
+			if err != nil {
+				return err
+			}
+			// Sunday is 0 or 7
+			s.dow |= days&(1<<7-1) | days>>7
+		}
+	}
+	return nil
+}
+
+// parseCronField returns the bits of the values of a comma separated list of
+// values, ranges and steps, from min to max.
+func parseCronField(field string, min, max int, names []string) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(field, ",") {
+		rangeSpec, stepSpec, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepSpec); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepSpec)
+			}
+		}
+
+		var start, end int
+		switch {
+		case rangeSpec == "*" || rangeSpec == "?":
+			start, end = min, max
+		case strings.Contains(rangeSpec, "-"):
+			from, to, _ := strings.Cut(rangeSpec, "-")
+			var err error
+			if start, err = parseCronValue(from, min, max, names); err != nil {
+				return 0, err
+			}
+			if end, err = parseCronValue(to, min, max, names); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range %q", rangeSpec)
+			}
+		default:
+			var err error
+			if start, err = parseCronValue(rangeSpec, min, max, names); err != nil {
+				return 0, err
+			}
+			end = start
+			if hasStep {
+				end = max
+			}
+		}
+		for v := start; v <= end; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+func parseCronValue(s string, min, max int, names []string) (int, error) {
+	for i, name := range names {
+		if strings.EqualFold(s, name) {
+			return i + min, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < min || v > max {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return v, nil
+}
+
+// matchDay returns true if the day of the civil time c fires.
+func (s *CronSchedule) matchDay(c time.Time) bool {
+	// Like cron, either if both are restricted, otherwise both, where "*"
+	// matches all the days
+	if s.domStar || s.dowStar {
+		return s.matchDayOfMonth(c) && s.matchDayOfWeek(c)
+	}
+	return s.matchDayOfMonth(c) || s.matchDayOfWeek(c)
+}
+
+func (s *CronSchedule) matchDayOfMonth(c time.Time) bool {
+	day := c.Day()
+	if s.dom&(1<<day) != 0 {
+		return true
+	}
+	last := daysInMonth(c.Year(), c.Month())
+	for _, n := range s.domLast {
+		if day == last-n {
+			return true
+		}
+	}
+	if s.domLastWeekday && day == nearestWeekday(c.Year(), c.Month(), last, last) {
+		return true
+	}
+	for _, n := range s.domNearestWeekday {
+		if n <= last && day == nearestWeekday(c.Year(), c.Month(), n, last) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *CronSchedule) matchDayOfWeek(c time.Time) bool {
+	weekday := int(c.Weekday())
+	if s.dow&(1<<weekday) != 0 {
+		return true
+	}
+	if s.dowLast&(1<<weekday) != 0 && c.Day()+7 > daysInMonth(c.Year(), c.Month()) {
+		return true
+	}
+	for _, nth := range s.dowNth {
+		if weekday == nth[0] && (c.Day()-1)/7+1 == nth[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// nearestWeekday returns the day of the weekday nearest day, in the same month
+// of last days.
+func nearestWeekday(year int, month time.Month, day, last int) int {
+	switch time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return day + 2
+		}
+		return day - 1
+	case time.Sunday:
+		if day == last {
+			return day - 2
+		}
+		return day + 1
+	default:
+		return day
+	}
+}
+
+// The years searched for a fire time, enough for "0 0 29 2 MON#5"
+const cronSearchYears = 30
+
+// Next returns the first fire time after after, in the location of the
+// schedule, or the zero time if there is none.
+func (s *CronSchedule) Next(after time.Time) time.Time {
+	// Search the civil times, the wall clock times as UTC, and then resolve
+	// them in the location
+	c := civilTime(after.In(s.loc)).Truncate(time.Second).Add(time.Second)
+	limit := c.Year() + cronSearchYears
+
+	for c.Year() <= limit {
+		c = s.nextCivil(c, limit)
+		if c.IsZero() {
+			break
+		}
+		if t, ok := s.resolve(c); ok && t.After(after) {
+			return t
+		}
+		c = c.Add(time.Second)
+	}
+	return time.Time{}
+}
+
+// Prev returns the last fire time before before, in the location of the
+// schedule, or the zero time if there is none.
+func (s *CronSchedule) Prev(before time.Time) time.Time {
+	c := civilTime(before.In(s.loc))
+	if truncated := c.Truncate(time.Second); truncated.Equal(c) {
+		c = c.Add(-time.Second)
+	} else {
+		c = truncated
+	}
+	limit := c.Year() - cronSearchYears
+
+	for c.Year() >= limit {
+		c = s.prevCivil(c, limit)
+		if c.IsZero() {
+			break
+		}
+		if t, ok := s.resolve(c); ok && t.Before(before) {
+			return t
+		}
+		c = c.Add(-time.Second)
+	}
+	return time.Time{}
+}
+
+// nextCivil returns the first civil fire time from c, or the zero time if
+// none until the limit year.
+func (s *CronSchedule) nextCivil(c time.Time, limit int) time.Time {
+wrap:
+	// Every rollover of a day, hour, minute or second comes back here, so
+	// expressions where no day ever matches stop at the limit too
+	if c.Year() > limit {
+		return time.Time{}
+	}
+	for s.month&(1<<int(c.Month())) == 0 {
+		c = time.Date(c.Year(), c.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		if c.Year() > limit {
+			return time.Time{}
+		}
+	}
+	for !s.matchDay(c) {
+		c = time.Date(c.Year(), c.Month(), c.Day()+1, 0, 0, 0, 0, time.UTC)
+		if c.Day() == 1 {
+			goto wrap
+		}
+	}
+	for s.hour&(1<<c.Hour()) == 0 {
+		c = c.Truncate(time.Hour).Add(time.Hour)
+		if c.Hour() == 0 {
+			goto wrap
+		}
+	}
+	for s.minute&(1<<c.Minute()) == 0 {
+		c = c.Truncate(time.Minute).Add(time.Minute)
+		if c.Minute() == 0 {
+			goto wrap
+		}
+	}
+	for s.second&(1<<c.Second()) == 0 {
+		c = c.Add(time.Second)
+		if c.Second() == 0 {
+			goto wrap
+		}
+	}
+	return c
+}
+
+// prevCivil returns the last civil fire time until c, or the zero time if
+// none since the limit year.
+func (s *CronSchedule) prevCivil(c time.Time, limit int) time.Time {
+wrap:
+	if c.Year() < limit {
+		return time.Time{}
+	}
+	for s.month&(1<<int(c.Month())) == 0 {
+		c = time.Date(c.Year(), c.Month(), 1, 0, 0, 0, 0, time.UTC).Add(-time.Second)
+		if c.Year() < limit {
+			return time.Time{}
+		}
+	}
+	for !s.matchDay(c) {
+		month := c.Month()
+		c = time.Date(c.Year(), c.Month(), c.Day(), 0, 0, 0, 0, time.UTC).Add(-time.Second)
+		if c.Month() != month {
+			goto wrap
+		}
+	}
+	for s.hour&(1<<c.Hour()) == 0 {
+		c = c.Truncate(time.Hour).Add(-time.Second)
+		if c.Hour() == 23 {
+			goto wrap
+		}
+	}
+	for s.minute&(1<<c.Minute()) == 0 {
+		c = c.Truncate(time.Minute).Add(-time.Second)
+		if c.Minute() == 59 {
+			goto wrap
+		}
+	}
+	for s.second&(1<<c.Second()) == 0 {
+		c = c.Add(-time.Second)
+		if c.Second() == 59 {
+			goto wrap
+		}
+	}
+	return c
+}
+
+// resolve returns the instant of the civil time c in the location, by the
+// DST policy, and false if it doesn't fire.
+func (s *CronSchedule) resolve(c time.Time) (time.Time, bool) {
+	t := time.Date(c.Year(), c.Month(), c.Day(), c.Hour(), c.Minute(), c.Second(), 0, s.loc)
+
+	if !civilTime(t).Equal(c) {
+		// Skipped by a transition, time.Date moved it past the transition
+		if s.dst == CronDSTSkip {
+			return time.Time{}, false
+		}
+		start, _ := t.ZoneBounds()
+		return start, true
+	}
+
+	// Repeated by a transition, fire at the first occurrence
+	if start, _ := t.ZoneBounds(); !start.IsZero() {
+		_, offsetBefore := start.Add(-time.Second).Zone()
+		if first := c.Add(-time.Duration(offsetBefore) * time.Second).In(s.loc); first.Before(t) && civilTime(first).Equal(c) {
+			return first, true
+		}
+	}
+	return t, true
+}
+
+// civilTime returns the wall clock time of t as UTC.
+func civilTime(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
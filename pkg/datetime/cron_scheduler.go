@@ -0,0 +1,156 @@
+package datetime
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+type cronJob struct {
+	name     string
+	schedule *CronSchedule
+	run      func(ctx context.Context, at time.Time)
+	next     time.Time
+}
+
+// CronScheduler runs jobs on cron schedules, with the time of a TimeProvider,
+// so that it can be tested with ManualTimeProvider:
+//
+//	scheduler := datetime.NewCronScheduler(timeProvider)
+//	err := scheduler.Add("reminders", datetime.MustParseCron("*/5 * * * *", datetime.CronOptions{}), sendReminders)
+//	go scheduler.Run(ctx)
+//
+// The jobs run one at a time in Run, long running jobs should start their
+// own goroutines. The fire times missed, such as while a job was running or
+// the process was suspended, run once. It is safe for concurrent use.
+type CronScheduler struct {
+	tp   TimeProvider
+	mu   sync.Mutex
+	jobs map[string]*cronJob
+	wake context.CancelFunc
+}
+
+// NewCronScheduler returns a scheduler without jobs on the time of tp.
+func NewCronScheduler(tp TimeProvider) *CronScheduler {
+	return &CronScheduler{tp: tp, jobs: make(map[string]*cronJob)}
+}
+
+// Add adds the job name, that run is called for with the fire times of
+// schedule after now.
+func (s *CronScheduler) Add(name string, schedule *CronSchedule, run func(ctx context.Context, at time.Time)) error {
+	next := schedule.Next(s.tp.Now())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[name]; ok {
+		return fmt.Errorf("%w: cron job %q already exists", ErrInvalidValue, name)
+	}
+	s.jobs[name] = &cronJob{name: name, schedule: schedule, run: run, next: next}
+	s.wakeUp()
+	return nil
+}
+
+// Remove removes the job name, and returns false if there is no such job.
+func (s *CronScheduler) Remove(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[name]; !ok {
+		return false
+	}
+	delete(s.jobs, name)
+	s.wakeUp()
+	return true
+}
+
+// Next returns the next fire time of the job name, zero if none, and false
+// if there is no such job.
+func (s *CronScheduler) Next(name string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[name]
+	if !ok {
+		return time.Time{}, false
+	}
+	return job.next, true
+}
+
+// Run runs the jobs when they are due until ctx is done, and returns the
+// error of ctx.
+func (s *CronScheduler) Run(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		now := s.tp.Now()
+		sleepCtx, cancel := context.WithCancel(ctx)
+
+		s.mu.Lock()
+		var due []*cronJob
+		var earliest time.Time
+		for _, job := range s.jobs {
+			switch {
+			case job.next.IsZero():
+			case !job.next.After(now):
+				due = append(due, job)
+			case earliest.IsZero() || job.next.Before(earliest):
+				earliest = job.next
+			}
+		}
+		s.wake = cancel
+		s.mu.Unlock()
+
+		if len(due) > 0 {
+			cancel()
+			sort.Slice(due, func(i, j int) bool {
+				if !due[i].next.Equal(due[j].next) {
+					return due[i].next.Before(due[j].next)
+				}
+				return due[i].name < due[j].name
+			})
+			for _, job := range due {
+				s.runJob(ctx, job)
+			}
+			continue
+		}
+
+		if earliest.IsZero() {
+			sleepUntilDone(sleepCtx, s.tp)
+		} else {
+			_ = SleepUntil(sleepCtx, s.tp, earliest)
+		}
+		cancel()
+	}
+}
+
+func (s *CronScheduler) runJob(ctx context.Context, job *cronJob) {
+	if ctx.Err() != nil {
+		return
+	}
+	// Removed, possibly by an earlier due job
+	s.mu.Lock()
+	removed := s.jobs[job.name] != job
+	s.mu.Unlock()
+	if removed {
+		return
+	}
+
+	job.run(ctx, job.next)
+	next := job.schedule.Next(s.tp.Now())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job.next = next
+}
+
+// wakeUp makes Run check the jobs again, the caller must hold s.mu.
+func (s *CronScheduler) wakeUp() {
+	if s.wake != nil {
+		s.wake()
+	}
+}
@@ -0,0 +1,136 @@
+package datetime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type cronRun struct {
+	job string
+	at  time.Time
+}
+
+func Test_CronScheduler(t *testing.T) {
+	_require := require.New(t)
+
+	stockholm, err := LoadLocation("Europe/Stockholm")
+	_require.Nil(err)
+	start := time.Date(2024, 3, 30, 23, 50, 0, 0, stockholm)
+	tp := NewManualTimeProvider(start)
+	scheduler := NewCronScheduler(tp)
+
+	runs := make(chan cronRun, 100)
+	job := func(name string) func(ctx context.Context, at time.Time) {
+		return func(ctx context.Context, at time.Time) { runs <- cronRun{job: name, at: at} }
+	}
+	opts := CronOptions{Location: stockholm}
+	_require.Nil(scheduler.Add("reminders", MustParseCron("*/30 * * * *", opts), job("reminders")))
+	_require.Nil(scheduler.Add("report", MustParseCron("30 2 * * *", opts), job("report")))
+	_require.ErrorIs(scheduler.Add("report", MustParseCron("@daily", opts), job("report")), ErrInvalidValue)
+
+	next, ok := scheduler.Next("report")
+	_require.True(ok)
+	_require.Equal(time.Date(2024, 3, 31, 1, 0, 0, 0, time.UTC), next.UTC()) // skipped 02:30, at 03:00 CEST
+	_, ok = scheduler.Next("unknown")
+	_require.False(ok)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- scheduler.Run(ctx) }()
+
+	// advance advances the time to the next sleeper, and returns the runs
+	advance := func(d time.Duration) []cronRun {
+		_require.Eventually(func() bool { return tp.Sleepers() == 1 }, time.Second, time.Millisecond)
+		tp.Advance(d)
+		_require.Eventually(func() bool { return tp.Sleepers() == 1 }, time.Second, time.Millisecond)
+		var actual []cronRun
+		for len(runs) > 0 {
+			actual = append(actual, <-runs)
+		}
+		return actual
+	}
+
+	_require.Empty(advance(5 * time.Minute))
+	_require.Equal([]cronRun{{"reminders", start.Add(10 * time.Minute)}}, advance(5*time.Minute))
+
+	for i := 1; i <= 3; i++ {
+		_require.Equal([]cronRun{{"reminders", start.Add(10*time.Minute + time.Duration(i)*30*time.Minute)}}, advance(30*time.Minute))
+	}
+
+	// Over the DST transition, both at 03:00 CEST, by name
+	transition := time.Date(2024, 3, 31, 1, 0, 0, 0, time.UTC).In(stockholm)
+	_require.Equal([]cronRun{
+		{"reminders", transition},
+		{"report", transition},
+	}, advance(transition.Sub(tp.Now())))
+
+	// Missed fire times run once
+	tp.Advance(3 * time.Hour)
+	_require.Eventually(func() bool { return len(runs) == 1 }, time.Second, time.Millisecond)
+	_require.Equal(cronRun{"reminders", transition.Add(30 * time.Minute)}, <-runs)
+	next, _ = scheduler.Next("reminders")
+	_require.Equal(transition.Add(3*time.Hour+30*time.Minute), next)
+
+	// Removed and added while running
+	_require.True(scheduler.Remove("reminders"))
+	_require.False(scheduler.Remove("reminders"))
+	_require.Nil(scheduler.Add("every-minute", MustParseCron("* * * * *", opts), job("every-minute")))
+	_require.Equal([]cronRun{{"every-minute", tp.Now().Add(time.Minute)}}, advance(time.Minute))
+
+	cancel()
+	_require.ErrorIs(<-done, context.Canceled)
+}
+
+func Test_CronScheduler_NoJobs(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewManualTimeProvider(time.Date(2024, 2, 14, 8, 0, 0, 0, time.UTC))
+	scheduler := NewCronScheduler(tp)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- scheduler.Run(ctx) }()
+
+	// Waits for jobs
+	ran := make(chan time.Time, 1)
+	time.Sleep(10 * time.Millisecond)
+	_require.Nil(scheduler.Add("job", MustParseCron("0 9 * * *", CronOptions{}), func(ctx context.Context, at time.Time) { ran <- at }))
+	_require.Eventually(func() bool { return tp.Sleepers() == 1 }, time.Second, time.Millisecond)
+	tp.Advance(time.Hour)
+	_require.Equal(time.Date(2024, 2, 14, 9, 0, 0, 0, time.UTC), <-ran)
+
+	cancel()
+	_require.ErrorIs(<-done, context.Canceled)
+}
+
+func Test_CronScheduler_RemovedWhileDue(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewManualTimeProvider(time.Date(2024, 2, 14, 8, 0, 0, 0, time.UTC))
+	scheduler := NewCronScheduler(tp)
+
+	// Due at the same time, "a" runs first and removes "b"
+	runs := make(chan string, 2)
+	daily := MustParseCron("0 9 * * *", CronOptions{})
+	_require.Nil(scheduler.Add("a", daily, func(ctx context.Context, at time.Time) {
+		scheduler.Remove("b")
+		runs <- "a"
+	}))
+	_require.Nil(scheduler.Add("b", daily, func(ctx context.Context, at time.Time) { runs <- "b" }))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- scheduler.Run(ctx) }()
+
+	_require.Eventually(func() bool { return tp.Sleepers() == 1 }, time.Second, time.Millisecond)
+	tp.Advance(time.Hour)
+	_require.Equal("a", <-runs)
+	_require.Eventually(func() bool { return tp.Sleepers() == 1 }, time.Second, time.Millisecond)
+	_require.Empty(runs)
+
+	cancel()
+	_require.ErrorIs(<-done, context.Canceled)
+}
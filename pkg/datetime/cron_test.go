@@ -0,0 +1,204 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseCron(t *testing.T) {
+	_require := require.New(t)
+
+	for _, expr := range []string{
+		"* * * * *",
+		"*/15 9-17 * * MON-FRI",
+		"0 0 1,15 * ?",
+		"30 */10 * * * *",
+		"0 0 L * *",
+		"0 0 L-3,LW,15W * *",
+		"0 0 ? * 5L,FRI#3",
+		"0 12 * JAN-MAR,dec sun",
+		"0 0 * * 7",
+		"@daily",
+		"@Hourly",
+		"CRON_TZ=Europe/Stockholm 0 9 * * *",
+		"TZ=UTC @weekly",
+	} {
+		s, err := ParseCron(expr, CronOptions{})
+		_require.Nil(err, expr)
+		_require.Equal(expr, s.String())
+	}
+
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * L-31 * *",
+		"* * 32W * *",
+		"* * * * 5#6",
+		"* * * * FOO",
+		"@every 5m",
+		"CRON_TZ=Mars/Olympus 0 9 * * *",
+	} {
+		_, err := ParseCron(expr, CronOptions{})
+		_require.ErrorIs(err, ErrInvalidValue, expr)
+	}
+
+	stockholm := MustParseCron("CRON_TZ=Europe/Stockholm 0 9 * * *", CronOptions{Location: time.UTC}).Location()
+	_require.Equal("Europe/Stockholm", stockholm.String())
+	_require.Equal(time.UTC, MustParseCron("@daily", CronOptions{}).Location())
+	_require.Panics(func() { MustParseCron("@never", CronOptions{}) })
+}
+
+// requireCronTimes requires the fire times of the expression after from, in
+// UTC.
+func requireCronTimes(t *testing.T, s *CronSchedule, from string, expected ...string) {
+	t.Helper()
+
+	at, err := ParseISO8601DateTime(from)
+	require.Nil(t, err)
+	var actual []string
+	for range expected {
+		at = s.Next(at)
+		actual = append(actual, TimeToISO8601DateTimeString(at.UTC()))
+	}
+	require.Equal(t, expected, actual, s.String())
+}
+
+func Test_CronSchedule_Next(t *testing.T) {
+	cron := func(expr string) *CronSchedule {
+		return MustParseCron(expr, CronOptions{})
+	}
+
+	// Business hours, over a weekend
+	requireCronTimes(t, cron("*/15 9-17 * * MON-FRI"), "2024-02-16T17:40:00Z",
+		"2024-02-16T17:45:00Z", "2024-02-19T09:00:00Z", "2024-02-19T09:15:00Z")
+
+	// Seconds
+	requireCronTimes(t, cron("*/20 * * * * *"), "2024-02-14T08:00:30.5Z",
+		"2024-02-14T08:00:40Z", "2024-02-14T08:01:00Z", "2024-02-14T08:01:20Z")
+
+	// Descriptors
+	requireCronTimes(t, cron("@hourly"), "2024-02-14T08:00:00Z", "2024-02-14T09:00:00Z", "2024-02-14T10:00:00Z")
+	requireCronTimes(t, cron("@daily"), "2024-02-14T08:00:00Z", "2024-02-15T00:00:00Z", "2024-02-16T00:00:00Z")
+	requireCronTimes(t, cron("@weekly"), "2024-02-14T08:00:00Z", "2024-02-18T00:00:00Z", "2024-02-25T00:00:00Z")
+	requireCronTimes(t, cron("@monthly"), "2024-02-14T08:00:00Z", "2024-03-01T00:00:00Z", "2024-04-01T00:00:00Z")
+	requireCronTimes(t, cron("@yearly"), "2024-02-14T08:00:00Z", "2025-01-01T00:00:00Z", "2026-01-01T00:00:00Z")
+
+	// Last days of the month, in a leap year
+	requireCronTimes(t, cron("0 0 L * *"), "2024-01-15T00:00:00Z",
+		"2024-01-31T00:00:00Z", "2024-02-29T00:00:00Z", "2024-03-31T00:00:00Z", "2024-04-30T00:00:00Z")
+	requireCronTimes(t, cron("0 0 L-2 * *"), "2024-01-15T00:00:00Z",
+		"2024-01-29T00:00:00Z", "2024-02-27T00:00:00Z", "2024-03-29T00:00:00Z")
+
+	// The last weekday: Wednesday 2024-01-31, Thursday 02-29, Friday 03-29
+	// since the 31st is a Sunday, Friday 08-30 since the 31st is a Saturday
+	requireCronTimes(t, cron("0 0 LW * *"), "2024-01-15T00:00:00Z",
+		"2024-01-31T00:00:00Z", "2024-02-29T00:00:00Z", "2024-03-29T00:00:00Z", "2024-04-30T00:00:00Z")
+	requireCronTimes(t, cron("0 0 LW 8 *"), "2024-01-15T00:00:00Z", "2024-08-30T00:00:00Z")
+
+	// The nearest weekday, not crossing the month: Saturday 2024-06-01 moves
+	// to Monday the 3rd, Sunday 2024-03-31 to Friday the 29th, Saturday
+	// 2024-06-15 to Friday the 14th, Sunday 2024-09-15 to Monday the 16th
+	requireCronTimes(t, cron("0 0 1W 6 *"), "2024-01-01T00:00:00Z", "2024-06-03T00:00:00Z")
+	requireCronTimes(t, cron("0 0 31W 3 *"), "2024-01-01T00:00:00Z", "2024-03-29T00:00:00Z")
+	requireCronTimes(t, cron("0 0 15W 6,9 *"), "2024-01-01T00:00:00Z", "2024-06-14T00:00:00Z", "2024-09-16T00:00:00Z")
+	// Not in months without the day
+	requireCronTimes(t, cron("0 0 31W * *"), "2024-04-01T00:00:00Z", "2024-05-31T00:00:00Z", "2024-07-31T00:00:00Z")
+
+	// The last Friday, and the third Friday
+	requireCronTimes(t, cron("0 0 * * 5L"), "2024-02-01T00:00:00Z", "2024-02-23T00:00:00Z", "2024-03-29T00:00:00Z")
+	requireCronTimes(t, cron("0 0 * * FRI#3"), "2024-02-01T00:00:00Z", "2024-02-16T00:00:00Z", "2024-03-15T00:00:00Z")
+	requireCronTimes(t, cron("0 0 * * 1#5"), "2024-02-01T00:00:00Z", "2024-04-29T00:00:00Z", "2024-07-29T00:00:00Z")
+
+	// The day of the month or the week, when both are restricted
+	requireCronTimes(t, cron("0 0 13 * FRI"), "2024-02-01T00:00:00Z",
+		"2024-02-02T00:00:00Z", "2024-02-09T00:00:00Z", "2024-02-13T00:00:00Z", "2024-02-16T00:00:00Z")
+	// Both, when one is a step of "*"
+	requireCronTimes(t, cron("0 0 */2 * FRI"), "2024-02-01T00:00:00Z", "2024-02-09T00:00:00Z", "2024-02-23T00:00:00Z")
+
+	// Sunday as 7, February 29th
+	requireCronTimes(t, cron("0 0 * * 7"), "2024-02-14T00:00:00Z", "2024-02-18T00:00:00Z")
+	requireCronTimes(t, cron("0 0 29 2 *"), "2024-03-01T00:00:00Z", "2028-02-29T00:00:00Z", "2032-02-29T00:00:00Z")
+
+	// Never, also when every month is allowed: "*/31" is only the 1st, and
+	// with "*" it must also be the second Sunday
+	for _, expr := range []string{"0 0 30 2 *", "0 0 */31 * SUN#2", "0 0 31 4,6,9,11 *"} {
+		never := cron(expr)
+		require.True(t, never.Next(decoratorsNow).IsZero(), expr)
+		require.True(t, never.Prev(decoratorsNow).IsZero(), expr)
+	}
+}
+
+func Test_CronSchedule_Prev(t *testing.T) {
+	_require := require.New(t)
+
+	s := MustParseCron("*/15 9-17 * * MON-FRI", CronOptions{})
+	at := time.Date(2024, 2, 19, 9, 0, 0, 0, time.UTC)
+	_require.Equal(time.Date(2024, 2, 16, 17, 45, 0, 0, time.UTC), s.Prev(at))
+	_require.Equal(at, s.Prev(at.Add(time.Nanosecond)))
+	_require.Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		MustParseCron("0 0 L * *", CronOptions{}).Prev(time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)))
+
+	// Prev is the inverse of Next, also over the DST transitions
+	stockholm, err := LoadLocation("Europe/Stockholm")
+	_require.Nil(err)
+	for _, expr := range []string{"*/15 * * * *", "30 2 * * *", "0 0 LW * *", "0 9 * * MON#2,5L", "0 0 13 * FRI", "45 1-3 * * *"} {
+		for _, from := range []time.Time{time.Date(2024, 3, 30, 0, 0, 0, 0, stockholm), time.Date(2024, 10, 26, 0, 0, 0, 0, stockholm)} {
+			for _, policy := range []CronDSTPolicy{CronDSTRunOnce, CronDSTSkip} {
+				s := MustParseCron(expr, CronOptions{Location: stockholm, DSTPolicy: policy})
+				prev := s.Next(from)
+				for i := 0; i < 500; i++ {
+					next := s.Next(prev)
+					_require.True(next.After(prev), expr)
+					_require.Equal(prev, s.Prev(next), "%s %v", expr, next)
+					prev = next
+				}
+			}
+		}
+	}
+}
+
+func Test_CronSchedule_DST(t *testing.T) {
+	stockholm, err := LoadLocation("Europe/Stockholm")
+	require.Nil(t, err)
+	runOnce := CronOptions{Location: stockholm, DSTPolicy: CronDSTRunOnce}
+	skip := CronOptions{Location: stockholm, DSTPolicy: CronDSTSkip}
+
+	// 2024-03-31 02:00 CET is 03:00 CEST, 01:00Z, 02:30 is skipped
+	requireCronTimes(t, MustParseCron("30 2 * * *", runOnce), "2024-03-30T03:00:00+01:00",
+		"2024-03-31T01:00:00Z", "2024-04-01T00:30:00Z")
+	requireCronTimes(t, MustParseCron("30 2 * * *", skip), "2024-03-30T03:00:00+01:00",
+		"2024-04-01T00:30:00Z")
+	requireCronTimes(t, MustParseCron("*/30 * * * *", runOnce), "2024-03-31T01:15:00+01:00",
+		"2024-03-31T00:30:00Z", "2024-03-31T01:00:00Z", "2024-03-31T01:30:00Z")
+	requireCronTimes(t, MustParseCron("*/30 * * * *", skip), "2024-03-31T01:15:00+01:00",
+		"2024-03-31T00:30:00Z", "2024-03-31T01:00:00Z", "2024-03-31T01:30:00Z")
+	requireCronTimes(t, MustParseCron("15 2 * * *", skip), "2024-03-31T01:00:00+01:00",
+		"2024-04-01T00:15:00Z")
+
+	// 2024-10-27 03:00 CEST is 02:00 CET, 01:00Z, 02:00 to 03:00 is repeated
+	// but fires once
+	for _, opts := range []CronOptions{runOnce, skip} {
+		requireCronTimes(t, MustParseCron("30 2 * * *", opts), "2024-10-26T03:00:00+02:00",
+			"2024-10-27T00:30:00Z", "2024-10-28T01:30:00Z")
+		requireCronTimes(t, MustParseCron("*/30 * * * *", opts), "2024-10-27T01:45:00+02:00",
+			"2024-10-27T00:00:00Z", "2024-10-27T00:30:00Z", "2024-10-27T02:00:00Z", "2024-10-27T02:30:00Z")
+		// From the repeated hour
+		requireCronTimes(t, MustParseCron("*/30 * * * *", opts), "2024-10-27T02:10:00+01:00",
+			"2024-10-27T02:00:00Z")
+	}
+
+	s := MustParseCron("*/30 * * * *", runOnce)
+	require.Equal(t, time.Date(2024, 10, 27, 0, 30, 0, 0, time.UTC), s.Prev(time.Date(2024, 10, 27, 2, 0, 0, 0, time.UTC)).UTC())
+	require.Equal(t, stockholm, s.Next(decoratorsNow).Location())
+}
@@ -0,0 +1,116 @@
+package datetime
+
+import (
+	"cmp"
+	"fmt"
+	"time"
+
+	dpb "google.golang.org/genproto/googleapis/type/date"
+)
+
+// Date is a calendar date without a time of day or a location, like the
+// google.type.Date proto and the SQL "date" type.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// NewDate returns the Date for the given year, month and day. The values are
+// not normalized, use IsValid to check them.
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// DateOf returns the Date that t falls on in its own location.
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// ParseDate parses an ISO8601 "2006-01-02" formatted date.
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(ISO8601Date, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("%w: %q is not an ISO8601 date", ErrInvalidValue, s)
+	}
+	return DateOf(t), nil
+}
+
+// IsZero reports whether d is the zero Date.
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// IsValid reports whether d is an existing date, year 1 to 9999.
+func (d Date) IsValid() bool {
+	if d.Year < 1 || d.Year > 9999 {
+		return false
+	}
+	return DateOf(d.In(time.UTC)) == d
+}
+
+// In returns the start of the day d in the location l.
+func (d Date) In(l *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, l)
+}
+
+// AddDays returns the date n days after d.
+func (d Date) AddDays(n int) Date {
+	return DateOf(d.In(time.UTC).AddDate(0, 0, n))
+}
+
+// Weekday returns the day of the week of d.
+func (d Date) Weekday() time.Weekday {
+	return d.In(time.UTC).Weekday()
+}
+
+// Before reports whether d is before other.
+func (d Date) Before(other Date) bool {
+	return d.Compare(other) < 0
+}
+
+// After reports whether d is after other.
+func (d Date) After(other Date) bool {
+	return d.Compare(other) > 0
+}
+
+// Compare returns -1, 0 or +1 depending on whether d is before, equal to or
+// after other.
+func (d Date) Compare(other Date) int {
+	switch {
+	case d.Year != other.Year:
+		return cmp.Compare(d.Year, other.Year)
+	case d.Month != other.Month:
+		return cmp.Compare(int(d.Month), int(other.Month))
+	default:
+		return cmp.Compare(d.Day, other.Day)
+	}
+}
+
+// String returns d formatted as an ISO8601 date, "2006-01-02".
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, int(d.Month), d.Day)
+}
+
+// ProtoDateToDate returns a new Date based on the google.type.Date.
+func ProtoDateToDate(d *dpb.Date) (Date, error) {
+	if d == nil {
+		return Date{}, fmt.Errorf("%w: date parameter not set", ErrInvalidValue)
+	}
+
+	if d.GetYear() < 1 || d.GetMonth() < 1 || d.GetDay() < 1 {
+		return Date{}, fmt.Errorf("%w: year, month, day not set", ErrInvalidValue)
+	}
+
+	return NewDate(int(d.GetYear()), time.Month(d.GetMonth()), int(d.GetDay())), nil
+}
+
+// DateToProtoDate returns a new google.type.Date based on the Date.
+func DateToProtoDate(d Date) *dpb.Date {
+	return &dpb.Date{
+		Year:  int32(d.Year),
+		Month: int32(d.Month),
+		Day:   int32(d.Day),
+	}
+}
@@ -0,0 +1,79 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	dpb "google.golang.org/genproto/googleapis/type/date"
+	todpb "google.golang.org/genproto/googleapis/type/timeofday"
+)
+
+func Test_ParseDate(t *testing.T) {
+	_require := require.New(t)
+
+	date, err := ParseDate("2024-02-29")
+	_require.Nil(err)
+	_require.Equal(NewDate(2024, time.February, 29), date)
+	_require.Equal("2024-02-29", date.String())
+	_require.True(date.IsValid())
+	_require.Equal(time.Thursday, date.Weekday())
+	_require.Equal(NewDate(2024, time.March, 1), date.AddDays(1))
+	_require.True(date.Before(date.AddDays(1)))
+	_require.True(date.After(date.AddDays(-1)))
+
+	_, err = ParseDate("2023-02-29")
+	_require.ErrorIs(err, ErrInvalidValue)
+
+	_require.False(NewDate(2023, time.February, 29).IsValid())
+	_require.True(Date{}.IsZero())
+}
+
+func Test_ProtoDateToDate(t *testing.T) {
+	_require := require.New(t)
+
+	date, err := ProtoDateToDate(&dpb.Date{Year: 2024, Month: 2, Day: 14})
+	_require.Nil(err)
+	_require.Equal(NewDate(2024, time.February, 14), date)
+	_require.Equal(&dpb.Date{Year: 2024, Month: 2, Day: 14}, DateToProtoDate(date))
+
+	_, err = ProtoDateToDate(&dpb.Date{})
+	_require.ErrorIs(err, ErrInvalidValue)
+	_, err = ProtoDateToDate(nil)
+	_require.ErrorIs(err, ErrInvalidValue)
+}
+
+func Test_TimeOfDay(t *testing.T) {
+	_require := require.New(t)
+
+	timeOfDay, err := ParseTimeOfDay("09:05:30.5")
+	_require.Nil(err)
+	_require.Equal(TimeOfDay{Hour: 9, Minute: 5, Second: 30, Nanosecond: int(500 * time.Millisecond)}, timeOfDay)
+	_require.Equal("09:05:30.5", timeOfDay.String())
+	_require.Equal(9*time.Hour+5*time.Minute+30*time.Second+500*time.Millisecond, timeOfDay.SinceMidnight())
+	_require.True(timeOfDay.IsValid())
+	_require.Equal(1, timeOfDay.Compare(NewTimeOfDay(9, 0, 0)))
+
+	timeOfDay, err = ParseTimeOfDay("17:45")
+	_require.Nil(err)
+	_require.Equal("17:45:00", timeOfDay.String())
+
+	_, err = ParseTimeOfDay("25:00")
+	_require.ErrorIs(err, ErrInvalidValue)
+	_require.False(NewTimeOfDay(24, 0, 0).IsValid())
+
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	_require.Nil(err)
+	_require.Equal(
+		"2024-02-14T17:45:00+01:00",
+		TimeToISO8601DateTimeString(NewTimeOfDay(17, 45, 0).On(NewDate(2024, 2, 14), stockholm)),
+	)
+
+	proto := TimeOfDayToProtoTimeOfDay(NewTimeOfDay(17, 45, 0))
+	_require.Equal(&todpb.TimeOfDay{Hours: 17, Minutes: 45}, proto)
+	timeOfDay, err = ProtoTimeOfDayToTimeOfDay(proto)
+	_require.Nil(err)
+	_require.Equal(NewTimeOfDay(17, 45, 0), timeOfDay)
+	_, err = ProtoTimeOfDayToTimeOfDay(nil)
+	_require.ErrorIs(err, ErrInvalidValue)
+}
@@ -25,6 +25,10 @@ const (
 
 var ErrInvalidValue = errors.New("invalid value")
 
+// Distinguishes between a time zone and a UTC offset, like the zones created
+// by ProtoDateTimeToTime
+var utcOffsetZoneRegexp = regexp.MustCompile(`^UTC([+-][\d]{1,2})$`)
+
 func TimeToISO8601DateStringWrapper(t *time.Time) *wrapperspb.StringValue {
 	if t != nil {
 		return wrapperspb.String(TimeToISO8601DateString(*t))
@@ -162,11 +166,8 @@ func TimeToProtoDateTime(t time.Time) *dtpb.DateTime {
 	// If the location is a UTC offset, encode it as such in the proto.
 	zone, offset := t.Zone()
 
-	// distinguish between time zone and utc offset
-	var offsetRegexp = regexp.MustCompile(`^UTC([+-][\d]{1,2})$`)
-
 	// Use utc offset if match or empty
-	match := offsetRegexp.FindStringSubmatch(zone)
+	match := utcOffsetZoneRegexp.FindStringSubmatch(zone)
 	if len(zone) == 0 || len(match) > 0 {
 		if offset > 0 {
 			dt.TimeOffset = &dtpb.DateTime_UtcOffset{
@@ -183,6 +184,22 @@ func TimeToProtoDateTime(t time.Time) *dtpb.DateTime {
 }
 
 func ProtoDateTimeToTime(d *dtpb.DateTime) (time.Time, error) {
+	return protoDateTimeToTime(d, LoadLocation)
+}
+
+// ProtoDateTimeToTimeWithResolver is like ProtoDateTimeToTime, but resolves
+// the TimeZone with r. Use LenientZoneResolver for integrations that send
+// Windows zone names or abbreviations.
+func ProtoDateTimeToTimeWithResolver(d *dtpb.DateTime, r ZoneResolver) (time.Time, error) {
+	return protoDateTimeToTime(d, func(id string) (*time.Location, error) {
+		if id == "" {
+			return time.UTC, nil
+		}
+		return r.Resolve(id)
+	})
+}
+
+func protoDateTimeToTime(d *dtpb.DateTime, loadLocation func(string) (*time.Location, error)) (time.Time, error) {
 	if d == nil {
 		return time.Time{}, fmt.Errorf("%w: date parameter not set", ErrInvalidValue)
 	}
@@ -196,7 +213,7 @@ func ProtoDateTimeToTime(d *dtpb.DateTime) (time.Time, error) {
 	// Determine the location.
 	loc := time.UTC
 	if tz := d.GetTimeZone(); tz != nil {
-		loc, err = time.LoadLocation(tz.GetId())
+		loc, err = loadLocation(tz.GetId())
 		if err != nil {
 			return time.Time{}, err
 		}
@@ -261,3 +278,27 @@ func ProtoTimeOfDayToTime(t *todpb.TimeOfDay) (time.Time, error) {
 			int(t.GetHours()), int(t.GetMinutes()), int(t.GetSeconds()), int(t.GetNanos()), time.UTC),
 		nil
 }
+
+// DurationToProtoDuration returns a new google.protobuf.Duration based on the
+// provided time.Duration.
+func DurationToProtoDuration(d time.Duration) *durpb.Duration {
+	return durpb.New(d)
+}
+
+// ProtoDurationToDuration returns a new time.Duration based on the
+// google.protobuf.Duration, which must be valid and within the range of
+// time.Duration, about 292 years.
+func ProtoDurationToDuration(d *durpb.Duration) (time.Duration, error) {
+	if d == nil {
+		return 0, fmt.Errorf("%w: duration parameter not set", ErrInvalidValue)
+	}
+	if err := d.CheckValid(); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidValue, err)
+	}
+	// AsDuration saturates when out of range, then it doesn't convert back
+	duration := d.AsDuration()
+	if back := durpb.New(duration); back.Seconds != d.Seconds || back.Nanos != d.Nanos {
+		return 0, fmt.Errorf("%w: duration %ds is out of range", ErrInvalidValue, d.Seconds)
+	}
+	return duration, nil
+}
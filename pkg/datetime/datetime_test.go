@@ -486,3 +486,46 @@ func Test_ProtoTimeOfDayToTime(t *testing.T) {
 	_require.Equal(0, tm.Second())
 	_require.Equal(0, tm.Nanosecond())
 }
+
+func BenchmarkProtoDateTimeToTime(b *testing.B) {
+	d := &dtpb.DateTime{
+		Year: 2024, Month: 2, Day: 14, Hours: 9,
+		TimeOffset: &dtpb.DateTime_TimeZone{TimeZone: &dtpb.TimeZone{Id: "Europe/Stockholm"}},
+	}
+	for i := 0; i < b.N; i++ {
+		if _, err := ProtoDateTimeToTime(d); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTimeToProtoDateTime(b *testing.B) {
+	t := time.Date(2024, 2, 14, 9, 0, 0, 0, time.FixedZone("UTC+1", 3600))
+	for i := 0; i < b.N; i++ {
+		TimeToProtoDateTime(t)
+	}
+}
+
+func Test_DurationToProtoDuration(t *testing.T) {
+	_require := require.New(t)
+
+	_require.Equal(&durpb.Duration{Seconds: 90, Nanos: 500}, DurationToProtoDuration(90*time.Second+500))
+	_require.Equal(&durpb.Duration{Seconds: -1, Nanos: -500000000}, DurationToProtoDuration(-1500*time.Millisecond))
+
+	for _, d := range []time.Duration{0, time.Nanosecond, -time.Hour, 1<<63 - 1, -1 << 63} {
+		actual, err := ProtoDurationToDuration(DurationToProtoDuration(d))
+		_require.Nil(err)
+		_require.Equal(d, actual)
+	}
+
+	for _, d := range []*durpb.Duration{
+		nil,
+		{Seconds: 1, Nanos: -1},
+		{Seconds: 0, Nanos: 1e9},
+		{Seconds: 1 << 40},
+		{Seconds: 9223372036, Nanos: 854775808},
+	} {
+		_, err := ProtoDurationToDuration(d)
+		_require.ErrorIs(err, ErrInvalidValue, "%v", d)
+	}
+}
@@ -0,0 +1,525 @@
+// Package datetimesim is a discrete-event simulation harness for testing
+// background jobs, such as reminder dispatchers, over days of virtual time in
+// milliseconds.
+//
+// Participants are goroutines started with Sim.Go. They block only through
+// the simulation, by sleeping or waiting for a Signal, and the simulation
+// advances the virtual time to the next event once the running participant
+// is blocked. Only one participant runs at a time, in the order of the
+// events, so the simulation and its trace are deterministic.
+//
+//	sim := datetimesim.New(start)
+//	defer sim.Close()
+//	sim.Go("dispatcher", func(p *datetimesim.Participant) {
+//		for {
+//			dispatcher.SendDueReminders(p.Now())
+//			p.Sleep(time.Minute)
+//		}
+//	})
+//	err := sim.Run(72 * time.Hour)
+package datetimesim
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dentech-floss/datetime/pkg/datetime"
+)
+
+// ErrDeadlock is returned by Run when participants wait for signals that
+// can't be notified, since all the other participants are blocked too and no
+// events are left.
+var ErrDeadlock = errors.New("deadlock")
+
+// ErrStalled is returned by Run when the running participant doesn't block
+// through the simulation within Sim.StallTimeout of real time, such as when
+// it blocks on a channel or calls time.Sleep.
+var ErrStalled = errors.New("stalled")
+
+// EventKind is the kind of an Event of the trace.
+type EventKind string
+
+const (
+	EventStart  EventKind = "start"  // a participant is started
+	EventSleep  EventKind = "sleep"  // a participant sleeps until the time of the event
+	EventWake   EventKind = "wake"   // a participant wakes up
+	EventWait   EventKind = "wait"   // a participant waits for a signal
+	EventNotify EventKind = "notify" // a signal is notified
+	EventTimer  EventKind = "timer"  // a timer fires
+	EventDone   EventKind = "done"   // a participant returns
+)
+
+// Event is an entry of the trace of a simulation.
+type Event struct {
+	// At is the virtual time of the event, or the time slept until for
+	// EventSleep
+	At time.Time
+	// Name is the name of the participant, or of the timer or signal
+	Name string
+	Kind EventKind
+}
+
+func (e Event) String() string {
+	return fmt.Sprintf("%s %s %s", datetime.TimeToISO8601DateTimeString(e.At), e.Name, e.Kind)
+}
+
+// Sim is a simulation, driven by Run. It implements datetime.TimeProvider
+// with the virtual time.
+type Sim struct {
+	// StallTimeout is how long, in real time, a participant may run before
+	// Run fails with ErrStalled, 5 seconds if 0
+	StallTimeout time.Duration
+
+	mu       sync.Mutex
+	changed  chan struct{} // signaled when the running participant blocks
+	now      time.Time
+	queue    eventQueue
+	seq      int
+	running  *Participant
+	waiting  map[*Participant]*Signal
+	sleeping map[*Participant]bool
+	contexts map[*Participant]context.Context // of the cancellable sleeps
+	trace    []Event
+	err      error
+	closed   bool
+}
+
+// New returns a simulation starting at the virtual time start.
+func New(start time.Time) *Sim {
+	return &Sim{
+		changed:  make(chan struct{}, 1),
+		now:      start,
+		waiting:  make(map[*Participant]*Signal),
+		sleeping: make(map[*Participant]bool),
+		contexts: make(map[*Participant]context.Context),
+	}
+}
+
+// Now returns the virtual time.
+func (s *Sim) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.now
+}
+
+// Trace returns the events of the simulation so far, in order.
+func (s *Sim) Trace() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Event(nil), s.trace...)
+}
+
+// Go starts the participant name running f at the current virtual time, once
+// the running participant, if any, is blocked. It can be called by
+// participants and timers too.
+func (s *Sim) Go(name string, f func(p *Participant)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := &Participant{sim: s, name: name, f: f, wake: make(chan bool, 1)}
+	s.schedule(s.now, func() { s.start(p) })
+}
+
+// AfterFunc calls f once d of virtual time has passed, unless the timer is
+// stopped. f runs between the steps of the participants and must not block,
+// but may start participants and notify signals.
+func (s *Sim) AfterFunc(name string, d time.Duration, f func()) *Timer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := &Timer{sim: s}
+	s.schedule(s.now.Add(d), func() {
+		if t.stopped {
+			return
+		}
+		t.fired = true
+		s.record(name, EventTimer)
+		s.mu.Unlock()
+		defer s.mu.Lock()
+		f()
+	})
+	return t
+}
+
+// NewSignal returns a signal that participants can wait for, such as a job
+// being enqueued.
+func (s *Sim) NewSignal(name string) *Signal {
+	return &Signal{sim: s, name: name}
+}
+
+// Run runs the simulation for d of virtual time, and then sets the virtual
+// time to the end. It returns ErrDeadlock if participants wait for signals
+// that can't be notified anymore, ErrStalled if a participant doesn't block
+// through the simulation, or the panic of a participant.
+func (s *Sim) Run(d time.Duration) error {
+	s.mu.Lock()
+	end := s.now.Add(d)
+	s.mu.Unlock()
+	return s.run(end, false)
+}
+
+// RunUntilDone runs the simulation until all the participants have returned
+// and no timers are left.
+func (s *Sim) RunUntilDone() error {
+	return s.run(time.Time{}, true)
+}
+
+func (s *Sim) run(end time.Time, untilDone bool) error {
+	for {
+		if err := s.waitUntilBlocked(); err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		if s.err != nil {
+			s.mu.Unlock()
+			return s.err
+		}
+		s.wakeCancelled()
+		if s.queue.Len() == 0 {
+			err := s.deadlock()
+			if !untilDone && err == nil {
+				s.now = end
+			}
+			s.mu.Unlock()
+			return err
+		}
+		next := s.queue[0]
+		if !untilDone && next.at.After(end) {
+			s.now = end
+			s.mu.Unlock()
+			return nil
+		}
+		heap.Pop(&s.queue)
+		if next.cancelled {
+			s.mu.Unlock()
+			continue
+		}
+		if next.at.After(s.now) {
+			s.now = next.at
+		}
+		next.fire()
+		s.mu.Unlock()
+	}
+}
+
+// waitUntilBlocked waits until the running participant, if any, blocks.
+func (s *Sim) waitUntilBlocked() error {
+	timeout := s.StallTimeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		s.mu.Lock()
+		running := s.running
+		s.mu.Unlock()
+		if running == nil {
+			return nil
+		}
+		select {
+		case <-s.changed:
+		case <-timer.C:
+			return fmt.Errorf("%w: participant %s did not block within %s", ErrStalled, running.name, timeout)
+		}
+	}
+}
+
+// wakeCancelled wakes the sleeping participants whose context is done, at
+// the current virtual time and in the order they started sleeping. The
+// contexts are checked between the steps of the participants, so that
+// cancelling from a participant is deterministic. The caller must hold s.mu.
+func (s *Sim) wakeCancelled() {
+	var cancelled []*Participant
+	for p, ctx := range s.contexts {
+		if ctx.Err() != nil {
+			cancelled = append(cancelled, p)
+		}
+	}
+	sort.Slice(cancelled, func(i, j int) bool { return cancelled[i].sleep.seq < cancelled[j].sleep.seq })
+	for _, p := range cancelled {
+		p := p
+		p.sleepErr = s.contexts[p].Err()
+		p.sleep.cancelled = true
+		delete(s.contexts, p)
+		s.schedule(s.now, func() { s.resume(p) })
+	}
+}
+
+// deadlock returns ErrDeadlock if participants wait for signals, with no
+// events left. The caller must hold s.mu.
+func (s *Sim) deadlock() error {
+	if len(s.waiting) == 0 {
+		return nil
+	}
+	var waiting []string
+	for p, signal := range s.waiting {
+		waiting = append(waiting, p.name+" waits for "+signal.name)
+	}
+	sort.Strings(waiting)
+	return fmt.Errorf("%w at %s: %s", ErrDeadlock, datetime.TimeToISO8601DateTimeString(s.now), strings.Join(waiting, ", "))
+}
+
+// Close stops all the blocked participants, their Sleep and Wait calls exit
+// the goroutines, so that no goroutines are leaked by the test.
+func (s *Sim) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for p := range s.sleeping {
+		p.wake <- false
+	}
+	for p := range s.waiting {
+		p.wake <- false
+	}
+	s.sleeping = map[*Participant]bool{}
+	s.waiting = map[*Participant]*Signal{}
+	s.contexts = map[*Participant]context.Context{}
+	s.queue = nil
+}
+
+// schedule adds fire to the events at the time at. The caller must hold s.mu.
+func (s *Sim) schedule(at time.Time, fire func()) *event {
+	s.seq++
+	e := &event{at: at, seq: s.seq, fire: fire}
+	heap.Push(&s.queue, e)
+	return e
+}
+
+// record adds an event at the current virtual time to the trace. The caller
+// must hold s.mu.
+func (s *Sim) record(name string, kind EventKind) {
+	s.trace = append(s.trace, Event{At: s.now, Name: name, Kind: kind})
+}
+
+// start runs the participant p until it blocks. The caller must hold s.mu.
+func (s *Sim) start(p *Participant) {
+	s.record(p.name, EventStart)
+	s.running = p
+	go p.run()
+}
+
+// resume lets the blocked participant p run until it blocks again. The
+// caller must hold s.mu.
+func (s *Sim) resume(p *Participant) {
+	delete(s.sleeping, p)
+	delete(s.waiting, p)
+	delete(s.contexts, p)
+	s.record(p.name, EventWake)
+	s.running = p
+	p.wake <- true
+}
+
+// blocked marks the running participant as blocked. The caller must hold s.mu.
+func (s *Sim) blocked() {
+	s.running = nil
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Timer is a function scheduled by Sim.AfterFunc.
+type Timer struct {
+	sim     *Sim
+	stopped bool
+	fired   bool
+}
+
+// Stop prevents the timer from firing, it returns false if it already fired
+// or was stopped.
+func (t *Timer) Stop() bool {
+	t.sim.mu.Lock()
+	defer t.sim.mu.Unlock()
+
+	if t.stopped || t.fired {
+		return false
+	}
+	t.stopped = true
+	return true
+}
+
+// Signal is notified by participants or timers to wake the participants
+// waiting for it.
+type Signal struct {
+	sim  *Sim
+	name string
+}
+
+// Notify wakes all the participants waiting for the signal, one at a time in
+// the order they started waiting, once the running participant is blocked.
+func (sig *Signal) Notify() {
+	s := sig.sim
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.record(sig.name, EventNotify)
+	var waiting []*Participant
+	for p, signal := range s.waiting {
+		if signal == sig {
+			waiting = append(waiting, p)
+		}
+	}
+	sort.Slice(waiting, func(i, j int) bool { return waiting[i].waitSeq < waiting[j].waitSeq })
+	for _, p := range waiting {
+		p := p
+		s.schedule(s.now, func() { s.resume(p) })
+		// Blocked until resumed, but not waiting for the signal anymore
+		delete(s.waiting, p)
+		s.sleeping[p] = true
+	}
+}
+
+// Participant is a goroutine of a simulation. It implements
+// datetime.TimeProvider and datetime.Sleeper with the virtual time, so that
+// CronScheduler.Run and Sweeper.Run can be run by participants.
+type Participant struct {
+	sim      *Sim
+	name     string
+	f        func(p *Participant)
+	wake     chan bool
+	waitSeq  int
+	sleep    *event // the wake up of the current sleep
+	sleepErr error  // the error of the context of a cancelled sleep
+}
+
+// errClosed is the panic that unwinds participants when the simulation is
+// closed.
+var errClosed = errors.New("simulation closed")
+
+func (p *Participant) run() {
+	defer func() {
+		r := recover()
+		s := p.sim
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if r != nil && r != errClosed && s.err == nil {
+			s.err = fmt.Errorf("participant %s panicked: %v", p.name, r)
+		}
+		if r != errClosed {
+			s.record(p.name, EventDone)
+			s.blocked()
+		}
+	}()
+	p.f(p)
+}
+
+// Name returns the name of the participant.
+func (p *Participant) Name() string {
+	return p.name
+}
+
+// Now returns the virtual time.
+func (p *Participant) Now() time.Time {
+	return p.sim.Now()
+}
+
+// Sleep blocks the participant for d of virtual time.
+func (p *Participant) Sleep(d time.Duration) {
+	_ = p.SleepUntil(context.Background(), p.Now().Add(d))
+}
+
+// SleepUntil blocks the participant until the virtual time t, or yields to
+// the other participants at the current time if t is not after it. It
+// returns the error of ctx if ctx is done before, which is noticed between
+// the steps of the participants, such as when a participant cancels it. Only
+// the participant itself may call it, so it can't be the TimeProvider of a
+// Deadline.Context.
+func (p *Participant) SleepUntil(ctx context.Context, t time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s := p.sim
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		panic(errClosed)
+	}
+	if t.Before(s.now) {
+		t = s.now
+	}
+	s.trace = append(s.trace, Event{At: t, Name: p.name, Kind: EventSleep})
+	s.sleeping[p] = true
+	p.sleep = s.schedule(t, func() { s.resume(p) })
+	if ctx.Done() != nil {
+		s.contexts[p] = ctx
+	}
+	s.blocked()
+	s.mu.Unlock()
+
+	p.block()
+	err := p.sleepErr
+	p.sleepErr = nil
+	return err
+}
+
+// Wait blocks the participant until the signal is notified.
+func (p *Participant) Wait(sig *Signal) {
+	s := p.sim
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		panic(errClosed)
+	}
+	s.record(p.name, EventWait)
+	s.seq++
+	p.waitSeq = s.seq
+	s.waiting[p] = sig
+	s.blocked()
+	s.mu.Unlock()
+
+	p.block()
+}
+
+func (p *Participant) block() {
+	if !<-p.wake {
+		panic(errClosed)
+	}
+}
+
+// Sim returns the simulation of the participant, such as to start other
+// participants or notify signals.
+func (p *Participant) Sim() *Sim {
+	return p.sim
+}
+
+type event struct {
+	at        time.Time
+	seq       int
+	fire      func()
+	cancelled bool // skipped, without advancing the virtual time
+}
+
+// eventQueue is a heap of events by time, and in the order they were
+// scheduled at the same time.
+type eventQueue []*event
+
+func (q eventQueue) Len() int { return len(q) }
+
+func (q eventQueue) Less(i, j int) bool {
+	if q[i].at.Equal(q[j].at) {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].at.Before(q[j].at)
+}
+
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *eventQueue) Push(x any) { *q = append(*q, x.(*event)) }
+
+func (q *eventQueue) Pop() any {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
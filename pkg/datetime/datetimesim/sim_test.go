@@ -0,0 +1,216 @@
+package datetimesim
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dentech-floss/datetime/pkg/datetime"
+)
+
+var start = time.Date(2024, 2, 14, 0, 0, 0, 0, time.UTC)
+
+func Test_Sim_Sleep(t *testing.T) {
+	_require := require.New(t)
+
+	sim := New(start)
+	defer sim.Close()
+	var _ datetime.TimeProvider = sim
+
+	// Reminders every 10 minutes and a sweeper every hour, for 3 days
+	var reminders, sweeps []time.Time
+	sim.Go("reminders", func(p *Participant) {
+		for {
+			reminders = append(reminders, p.Now())
+			p.Sleep(10 * time.Minute)
+		}
+	})
+	sim.Go("sweeper", func(p *Participant) {
+		for {
+			p.Sleep(time.Hour)
+			sweeps = append(sweeps, p.Now())
+		}
+	})
+
+	realStart := time.Now()
+	_require.Nil(sim.Run(72 * time.Hour))
+	_require.Less(time.Since(realStart), time.Second)
+
+	_require.Equal(start.Add(72*time.Hour), sim.Now())
+	_require.Len(reminders, 6*72+1)
+	_require.Len(sweeps, 72)
+	_require.Equal(start.Add(time.Hour), sweeps[0])
+
+	_require.Equal([]Event{
+		{At: start, Name: "reminders", Kind: EventStart},
+		{At: start.Add(10 * time.Minute), Name: "reminders", Kind: EventSleep},
+		{At: start, Name: "sweeper", Kind: EventStart},
+		{At: start.Add(time.Hour), Name: "sweeper", Kind: EventSleep},
+		{At: start.Add(10 * time.Minute), Name: "reminders", Kind: EventWake},
+	}, sim.Trace()[:5])
+
+	// Continues where it stopped
+	_require.Nil(sim.Run(time.Hour))
+	_require.Len(sweeps, 73)
+}
+
+func Test_Sim_Deterministic(t *testing.T) {
+	trace := func() []Event {
+		sim := New(start)
+		defer sim.Close()
+		for _, name := range []string{"a", "b", "c"} {
+			interval := time.Duration(len(name)+1) * time.Minute
+			sim.Go(name, func(p *Participant) {
+				for {
+					p.Sleep(interval)
+				}
+			})
+		}
+		require.Nil(t, sim.Run(time.Hour))
+		return sim.Trace()
+	}
+
+	expected := trace()
+	for i := 0; i < 10; i++ {
+		require.Equal(t, expected, trace())
+	}
+}
+
+func Test_Sim_Signal(t *testing.T) {
+	_require := require.New(t)
+
+	sim := New(start)
+	defer sim.Close()
+
+	enqueued := sim.NewSignal("enqueued")
+	var queue []string
+	var sent []string
+
+	sim.Go("dispatcher", func(p *Participant) {
+		for len(sent) < 2 {
+			for len(queue) == 0 {
+				p.Wait(enqueued)
+			}
+			sent = append(sent, p.Now().Format("15:04")+" "+queue[0])
+			queue = queue[1:]
+		}
+	})
+	sim.Go("booking", func(p *Participant) {
+		p.Sleep(90 * time.Minute)
+		queue = append(queue, "confirmation")
+		enqueued.Notify()
+		p.Sleep(time.Hour)
+		queue = append(queue, "reminder")
+		enqueued.Notify()
+	})
+
+	_require.Nil(sim.Run(24 * time.Hour))
+	_require.Equal([]string{"01:30 confirmation", "02:30 reminder"}, sent)
+	_require.Contains(sim.Trace(), Event{At: start.Add(150 * time.Minute), Name: "booking", Kind: EventDone})
+}
+
+func Test_Sim_Deadlock(t *testing.T) {
+	sim := New(start)
+	defer sim.Close()
+
+	never := sim.NewSignal("never")
+	sim.Go("waiter", func(p *Participant) {
+		p.Sleep(time.Hour)
+		p.Wait(never)
+	})
+
+	err := sim.Run(24 * time.Hour)
+	require.ErrorIs(t, err, ErrDeadlock)
+	require.Equal(t, "deadlock at 2024-02-14T01:00:00Z: waiter waits for never", err.Error())
+}
+
+func Test_Sim_AfterFunc(t *testing.T) {
+	_require := require.New(t)
+
+	sim := New(start)
+	defer sim.Close()
+
+	var fired []time.Time
+	sim.AfterFunc("timeout", 30*time.Minute, func() { fired = append(fired, sim.Now()) })
+	stopped := sim.AfterFunc("cancelled", time.Hour, func() { fired = append(fired, sim.Now()) })
+	_require.True(stopped.Stop())
+	_require.False(stopped.Stop())
+
+	// Timers can start participants
+	sim.AfterFunc("later", 2*time.Hour, func() {
+		sim.Go("late", func(p *Participant) { fired = append(fired, p.Now()) })
+	})
+
+	_require.Nil(sim.RunUntilDone())
+	_require.Equal([]time.Time{start.Add(30 * time.Minute), start.Add(2 * time.Hour)}, fired)
+	_require.Equal(start.Add(2*time.Hour), sim.Now())
+}
+
+func Test_Sim_CronScheduler(t *testing.T) {
+	_require := require.New(t)
+
+	sim := New(start)
+	defer sim.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var scheduler *datetime.CronScheduler
+	var fired []string
+	var runErr error
+	run := func(name string) func(ctx context.Context, at time.Time) {
+		return func(ctx context.Context, at time.Time) {
+			fired = append(fired, at.Format("15:04")+" "+name)
+		}
+	}
+	sim.Go("scheduler", func(p *Participant) {
+		var _ datetime.Sleeper = p
+		scheduler = datetime.NewCronScheduler(p)
+		_require.Nil(scheduler.Add("hourly", datetime.MustParseCron("0 * * * *", datetime.CronOptions{}), run("hourly")))
+		runErr = scheduler.Run(ctx)
+	})
+
+	// Adding a job and cancelling wake up the sleeping scheduler
+	sim.Go("admin", func(p *Participant) {
+		p.Sleep(90 * time.Minute)
+		_require.Nil(scheduler.Add("quarter", datetime.MustParseCron("*/15 * * * *", datetime.CronOptions{}), run("quarter")))
+		p.Sleep(2 * time.Hour)
+		cancel()
+	})
+
+	_require.Nil(sim.RunUntilDone())
+	_require.ErrorIs(runErr, context.Canceled)
+	_require.Equal(start.Add(210*time.Minute), sim.Now())
+	_require.Equal([]string{
+		"01:00 hourly",
+		"01:45 quarter",
+		"02:00 hourly",
+		"02:00 quarter",
+		"02:15 quarter",
+		"02:30 quarter",
+		"02:45 quarter",
+		"03:00 hourly",
+		"03:00 quarter",
+		"03:15 quarter",
+	}, fired)
+}
+
+func Test_Sim_Errors(t *testing.T) {
+	sim := New(start)
+	sim.StallTimeout = 50 * time.Millisecond
+	block := make(chan struct{})
+	sim.Go("blocker", func(p *Participant) { <-block })
+	require.ErrorIs(t, sim.Run(time.Hour), ErrStalled)
+	close(block)
+
+	sim = New(start)
+	sim.Go("panicker", func(p *Participant) {
+		p.Sleep(time.Minute)
+		panic("oops")
+	})
+	err := sim.Run(time.Hour)
+	require.NotNil(t, err)
+	require.Equal(t, "participant panicker panicked: oops", err.Error())
+}
@@ -0,0 +1,132 @@
+// Package datetimetest provides assertions and fixtures for tests of code
+// using times, dates and the google.type protos, with readable ISO8601 diffs.
+//
+// Unlike require.Equal, the assertions compare instants and not time.Time
+// values, so monotonic clock readings and location pointers don't matter:
+//
+//	datetimetest.EqualInstant(t, datetimetest.MustParse("2024-02-14T08:00:00Z"), appointment.StartsAt)
+package datetimetest
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dentech-floss/datetime/pkg/datetime"
+
+	dtpb "google.golang.org/genproto/googleapis/type/datetime"
+)
+
+// TestingT is the part of *testing.T used by the assertions, which like
+// require stop the test on failure.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+	FailNow()
+}
+
+// EqualInstant asserts that expected and actual are the same instant, in any
+// location.
+func EqualInstant(t TestingT, expected, actual time.Time, msgAndArgs ...any) {
+	t.Helper()
+	if !expected.Equal(actual) {
+		fail(t, fmt.Sprintf(
+			"Not the same instant:\n"+
+				"expected: %s\n"+
+				"actual  : %s\n"+
+				"diff    : %s",
+			format(expected), format(actual), actual.Sub(expected),
+		), msgAndArgs)
+	}
+}
+
+// WithinDuration asserts that actual is at most delta from expected.
+func WithinDuration(t TestingT, expected, actual time.Time, delta time.Duration, msgAndArgs ...any) {
+	t.Helper()
+	if diff := actual.Sub(expected); diff < -delta || diff > delta {
+		fail(t, fmt.Sprintf(
+			"Not within %s:\n"+
+				"expected: %s\n"+
+				"actual  : %s\n"+
+				"diff    : %s",
+			delta, format(expected), format(actual), diff,
+		), msgAndArgs)
+	}
+}
+
+// SameDate asserts that expected and actual are on the same date in the
+// location l, such as the same day for a clinic.
+func SameDate(t TestingT, l *time.Location, expected, actual time.Time, msgAndArgs ...any) {
+	t.Helper()
+	if datetime.DateOf(expected.In(l)) != datetime.DateOf(actual.In(l)) {
+		fail(t, fmt.Sprintf(
+			"Not the same date in %s:\n"+
+				"expected: %s (%s)\n"+
+				"actual  : %s (%s)",
+			l, datetime.DateOf(expected.In(l)), format(expected.In(l)), datetime.DateOf(actual.In(l)), format(actual.In(l)),
+		), msgAndArgs)
+	}
+}
+
+// ProtoDateTimeEqual asserts that expected and actual are equal, with the
+// same wall clock time and the same time zone or UTC offset.
+func ProtoDateTimeEqual(t TestingT, expected, actual *dtpb.DateTime, msgAndArgs ...any) {
+	t.Helper()
+	if !proto.Equal(expected, actual) {
+		fail(t, fmt.Sprintf(
+			"Not equal:\n"+
+				"expected: %s\n"+
+				"actual  : %s",
+			formatProto(expected), formatProto(actual),
+		), msgAndArgs)
+	}
+}
+
+// format returns t as ISO8601 with as many fractional digits as needed, and
+// the location when it is not only an offset.
+func format(t time.Time) string {
+	s := datetime.FormatISO8601DateTime(t, datetime.FormatOptions{Precision: datetime.PrecisionTrimmed})
+	if name := t.Location().String(); name != "UTC" && name != "" {
+		s += " [" + name + "]"
+	}
+	return s
+}
+
+// formatProto returns d as an ISO8601 date time with its time zone or UTC
+// offset, without resolving the time zone.
+func formatProto(d *dtpb.DateTime) string {
+	if d == nil {
+		return "<nil>"
+	}
+	s := fmt.Sprintf("%04d-%02d-%02dT%02d:%02d:%02d", d.GetYear(), d.GetMonth(), d.GetDay(), d.GetHours(), d.GetMinutes(), d.GetSeconds())
+	if d.GetNanos() != 0 {
+		s += fmt.Sprintf(".%09d", d.GetNanos())
+	}
+	switch {
+	case d.GetTimeZone() != nil:
+		s += " [" + d.GetTimeZone().GetId() + "]"
+	case d.GetUtcOffset() != nil:
+		offset, sign := d.GetUtcOffset().GetSeconds(), "+"
+		if offset < 0 {
+			offset, sign = -offset, "-"
+		}
+		s += fmt.Sprintf("%s%02d:%02d", sign, offset/3600, offset/60%60)
+	default:
+		s += " (local time)"
+	}
+	return s
+}
+
+func fail(t TestingT, message string, msgAndArgs []any) {
+	t.Helper()
+	if len(msgAndArgs) > 0 {
+		if format, ok := msgAndArgs[0].(string); ok {
+			message += "\nmessage : " + fmt.Sprintf(format, msgAndArgs[1:]...)
+		} else {
+			message += "\nmessage : " + fmt.Sprint(msgAndArgs...)
+		}
+	}
+	t.Errorf("\n%s", message)
+	t.FailNow()
+}
@@ -0,0 +1,123 @@
+package datetimetest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dentech-floss/datetime/pkg/datetime"
+)
+
+// fakeT records the failures of the assertions.
+type fakeT struct {
+	messages []string
+	failed   bool
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.messages = append(t.messages, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) FailNow() {
+	t.failed = true
+}
+
+func Test_EqualInstant(t *testing.T) {
+	utc := MustParse("2024-02-14T08:00:00Z")
+	stockholm := MustParseIn("2024-02-14T08:00:00Z", "Europe/Stockholm")
+
+	// Passes despite the monotonic clock reading and the location
+	now := time.Now()
+	EqualInstant(t, now.UTC(), now)
+	EqualInstant(t, utc, stockholm)
+
+	fake := &fakeT{}
+	EqualInstant(fake, utc, stockholm.Add(90*time.Minute+500*time.Millisecond), "appointment %d", 1)
+	require.True(t, fake.failed)
+	require.Equal(t, []string{"\n" +
+		"Not the same instant:\n" +
+		"expected: 2024-02-14T08:00:00Z\n" +
+		"actual  : 2024-02-14T10:30:00.5+01:00 [Europe/Stockholm]\n" +
+		"diff    : 1h30m0.5s\n" +
+		"message : appointment 1",
+	}, fake.messages)
+}
+
+func Test_WithinDuration(t *testing.T) {
+	utc := MustParse("2024-02-14T08:00:00Z")
+	WithinDuration(t, utc, utc.Add(-time.Second), time.Second)
+
+	fake := &fakeT{}
+	WithinDuration(fake, utc, utc.Add(-2*time.Second), time.Second)
+	require.True(t, fake.failed)
+	require.Contains(t, fake.messages[0], "Not within 1s:")
+	require.Contains(t, fake.messages[0], "diff    : -2s")
+}
+
+func Test_SameDate(t *testing.T) {
+	stockholm := MustLoadLocation("Europe/Stockholm")
+
+	// Different dates in UTC, but the same in Stockholm
+	SameDate(t, stockholm, MustParse("2024-02-13T23:30:00Z"), MustParse("2024-02-14T12:00:00Z"))
+
+	fake := &fakeT{}
+	SameDate(fake, time.UTC, MustParse("2024-02-13T23:30:00Z"), MustParse("2024-02-14T12:00:00Z"))
+	require.True(t, fake.failed)
+	require.Equal(t, "\n"+
+		"Not the same date in UTC:\n"+
+		"expected: 2024-02-13 (2024-02-13T23:30:00Z)\n"+
+		"actual  : 2024-02-14 (2024-02-14T12:00:00Z)",
+		fake.messages[0])
+}
+
+func Test_ProtoDateTimeEqual(t *testing.T) {
+	ProtoDateTimeEqual(t,
+		MustProtoDateTime("2024-02-14T09:00:00+01:00", "Europe/Stockholm"),
+		datetime.TimeToProtoDateTime(time.Date(2024, 2, 14, 9, 0, 0, 0, MustLoadLocation("Europe/Stockholm"))),
+	)
+
+	fake := &fakeT{}
+	ProtoDateTimeEqual(fake,
+		MustProtoDateTime("2024-02-14T09:00:00+01:00", "Europe/Stockholm"),
+		MustProtoDateTime("2024-02-14T09:00:00.25+02:00", ""),
+	)
+	require.True(t, fake.failed)
+	require.Equal(t, "\n"+
+		"Not equal:\n"+
+		"expected: 2024-02-14T09:00:00 [Europe/Stockholm]\n"+
+		"actual  : 2024-02-14T09:00:00.250000000+02:00",
+		fake.messages[0])
+}
+
+func Test_Fixtures(t *testing.T) {
+	require.Equal(t, int32(2024), MustProtoDate("2024-02-29").GetYear())
+	require.Equal(t, int32(29), MustProtoDate("2024-02-29").GetDay())
+	require.Panics(t, func() { MustProtoDate("2023-02-29") })
+	require.Panics(t, func() { MustParse("yesterday") })
+	require.Panics(t, func() { MustLoadLocation("Europe/Stockholmm") })
+	require.Equal(t, "Europe/Stockholm", MustParseIn("2024-02-14T08:00:00Z", "Europe/Stockholm").Location().String())
+}
+
+func Test_TrickyInstants(t *testing.T) {
+	names := map[string]bool{}
+	for _, instant := range TrickyInstants() {
+		require.False(t, names[instant.Name], instant.Name)
+		names[instant.Name] = true
+
+		// They survive the round trip through the protos, except the first of
+		// a repeated hour since the proto has no UTC offset to tell them apart
+		t.Run(instant.Name, func(t *testing.T) {
+			proto := datetime.TimeToProtoDateTime(instant.Time)
+			back, err := datetime.ProtoDateTimeToTime(proto)
+			require.Nil(t, err)
+			ProtoDateTimeEqual(t, proto, datetime.TimeToProtoDateTime(back))
+			if instant.Name != "first of repeated hour" {
+				EqualInstant(t, instant.Time, back)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package datetimetest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dentech-floss/datetime/pkg/datetime"
+
+	dpb "google.golang.org/genproto/googleapis/type/date"
+	dtpb "google.golang.org/genproto/googleapis/type/datetime"
+)
+
+// MustParse returns the time of an ISO8601 string, keeping its offset, like
+// datetime.ISO8601StringToTime. It panics if s is invalid.
+func MustParse(s string) time.Time {
+	t, err := datetime.ISO8601StringToTime(s)
+	if err != nil {
+		panic(fmt.Sprintf("datetimetest: MustParse(%q): %v", s, err))
+	}
+	return t
+}
+
+// MustParseIn returns the time of an ISO8601 string in the location of the
+// IANA zone zoneID, such as MustParseIn("2024-03-31T03:00:00+02:00",
+// "Europe/Stockholm"). It panics if s or zoneID is invalid.
+func MustParseIn(s, zoneID string) time.Time {
+	return MustParse(s).In(MustLoadLocation(zoneID))
+}
+
+// MustLoadLocation returns the location of the IANA zone name, like
+// datetime.LoadLocation. It panics if name is invalid.
+func MustLoadLocation(name string) *time.Location {
+	l, err := datetime.LoadLocation(name)
+	if err != nil {
+		panic(fmt.Sprintf("datetimetest: MustLoadLocation(%q): %v", name, err))
+	}
+	return l
+}
+
+// MustProtoDate returns the google.type.Date of an ISO8601 date,
+// "2006-01-02". It panics if s is invalid.
+func MustProtoDate(s string) *dpb.Date {
+	d, err := datetime.ParseDate(s)
+	if err != nil {
+		panic(fmt.Sprintf("datetimetest: MustProtoDate(%q): %v", s, err))
+	}
+	return datetime.DateToProtoDate(d)
+}
+
+// MustProtoDateTime returns the google.type.DateTime of an ISO8601 date time
+// in the location of the IANA zone zoneID, or with the UTC offset of s if
+// zoneID is "". It panics if s or zoneID is invalid.
+func MustProtoDateTime(s, zoneID string) *dtpb.DateTime {
+	if zoneID == "" {
+		return datetime.TimeToProtoDateTime(MustParse(s))
+	}
+	return datetime.TimeToProtoDateTime(MustParseIn(s, zoneID))
+}
@@ -0,0 +1,46 @@
+package datetimetest
+
+import "time"
+
+// Instant is a named instant that is known to break date/time code.
+type Instant struct {
+	Name string
+	Time time.Time
+}
+
+// TrickyInstants returns instants at DST transitions, leap days, odd offsets
+// and the limits of the supported years, in the locations where they are
+// tricky. Run table tests over them:
+//
+//	for _, instant := range datetimetest.TrickyInstants() {
+//		t.Run(instant.Name, func(t *testing.T) { ... })
+//	}
+func TrickyInstants() []Instant {
+	stockholm := MustLoadLocation("Europe/Stockholm")
+	return []Instant{
+		{"unix epoch", time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"year 1", time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"year 9999 last nanosecond", time.Date(9999, 12, 31, 23, 59, 59, 999999999, time.UTC)},
+		{"32-bit unix time overflow", time.Date(2038, 1, 19, 3, 14, 8, 0, time.UTC)},
+		{"leap day", time.Date(2024, 2, 29, 12, 0, 0, 0, stockholm)},
+		{"day after leap day", time.Date(2024, 3, 1, 0, 0, 0, 0, stockholm)},
+		{"not a leap year 2100", time.Date(2100, 2, 28, 23, 59, 59, 0, time.UTC)},
+		{"leap year 2000", time.Date(2000, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"new year's eve last second", time.Date(2024, 12, 31, 23, 59, 59, 0, stockholm)},
+		{"last nanosecond of day", time.Date(2024, 2, 14, 23, 59, 59, 999999999, stockholm)},
+		{"before spring forward", time.Date(2024, 3, 31, 1, 59, 59, 0, stockholm)},
+		{"spring forward", time.Date(2024, 3, 31, 3, 0, 0, 0, stockholm)},
+		{"first of repeated hour", time.Date(2024, 10, 27, 0, 30, 0, 0, time.UTC).In(stockholm)},
+		{"second of repeated hour", time.Date(2024, 10, 27, 1, 30, 0, 0, time.UTC).In(stockholm)},
+		{"after fall back", time.Date(2024, 10, 27, 3, 0, 0, 0, stockholm)},
+		{"US spring forward", time.Date(2024, 3, 10, 3, 0, 0, 0, MustLoadLocation("America/New_York"))},
+		{"southern hemisphere DST", time.Date(2024, 1, 15, 9, 0, 0, 0, MustLoadLocation("Australia/Sydney"))},
+		{"half hour DST", time.Date(2024, 10, 6, 2, 30, 0, 0, MustLoadLocation("Australia/Lord_Howe"))},
+		{"quarter hour offset", time.Date(2024, 2, 14, 9, 0, 0, 0, MustLoadLocation("Asia/Kathmandu"))},
+		{"half hour offset", time.Date(2024, 2, 14, 9, 0, 0, 0, MustLoadLocation("Asia/Kolkata"))},
+		{"largest offset", time.Date(2024, 2, 14, 0, 30, 0, 0, MustLoadLocation("Pacific/Kiritimati"))},
+		{"smallest offset", time.Date(2024, 2, 14, 23, 30, 0, 0, MustLoadLocation("Etc/GMT+12"))},
+		{"fixed offset", time.Date(2024, 2, 14, 9, 0, 0, 0, time.FixedZone("UTC+1", 3600))},
+		{"after skipped day", time.Date(2011, 12, 31, 0, 0, 0, 0, MustLoadLocation("Pacific/Apia"))},
+	}
+}
@@ -0,0 +1,225 @@
+package datetime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	tspb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Deadline is an instant bound to a TimeProvider, such as when a token or a
+// booking hold expires. It is expired from the instant on, so that
+//
+//	if deadline.Expired() { ... }
+//
+// replaces both "!expiresAt.After(now)" and the off-by-one
+// "expiresAt.Before(now)". The zero value has no TimeProvider and must not be
+// used, a Deadline is immutable.
+type Deadline struct {
+	at time.Time
+	tp TimeProvider
+}
+
+// NewDeadline returns the deadline at the instant at, on the time of tp.
+func NewDeadline(tp TimeProvider, at time.Time) Deadline {
+	return Deadline{at: at, tp: tp}
+}
+
+// DeadlineAfter returns the deadline d after the current time of tp.
+func DeadlineAfter(tp TimeProvider, d time.Duration) Deadline {
+	return Deadline{at: tp.Now().Add(d), tp: tp}
+}
+
+// At returns the instant of the deadline.
+func (d Deadline) At() time.Time {
+	return d.at
+}
+
+// Expired returns true from the instant of the deadline on.
+func (d Deadline) Expired() bool {
+	return !d.tp.Now().Before(d.at)
+}
+
+// Remaining returns the time until the deadline, 0 once expired.
+func (d Deadline) Remaining() time.Duration {
+	return max(d.at.Sub(d.tp.Now()), 0)
+}
+
+// Extend returns the deadline moved later by ext, or earlier if negative.
+func (d Deadline) Extend(ext time.Duration) Deadline {
+	return Deadline{at: d.at.Add(ext), tp: d.tp}
+}
+
+// Before returns true if d is before other.
+func (d Deadline) Before(other Deadline) bool {
+	return d.at.Before(other.at)
+}
+
+// Context returns a context done at the deadline, with
+// context.DeadlineExceeded, or when parent is done.
+//
+// For a Sleeper, such as ManualTimeProvider, it is done once the provider
+// reaches the deadline, and has no real time Deadline. The Sleeper is waited
+// for in a goroutine, so a datetimesim.Participant, that only sleeps itself,
+// can't be used.
+//
+// Otherwise its deadline is the real time Remaining from now, so providers
+// ahead or behind the real clock, such as Offset, work too. A provider that
+// doesn't advance, such as NewFakeTimeProvider, is done once Remaining has
+// passed in real time, while Expired stays false.
+func (d Deadline) Context(parent context.Context) (context.Context, context.CancelFunc) {
+	sleeper, ok := d.tp.(Sleeper)
+	if !ok {
+		return context.WithDeadline(parent, time.Now().Add(d.Remaining()))
+	}
+
+	trigger := &sleeperDeadlineContext{Context: parent, done: make(chan struct{})}
+	if err := parent.Err(); err != nil {
+		trigger.finish(err)
+	} else if d.Expired() {
+		trigger.finish(context.DeadlineExceeded)
+	}
+	stop := context.AfterFunc(parent, func() { trigger.finish(parent.Err()) })
+	ctx, cancel := context.WithCancel(trigger)
+	if trigger.Err() == nil {
+		go func() {
+			if sleeper.SleepUntil(ctx, d.at) == nil {
+				trigger.finish(context.DeadlineExceeded)
+			}
+		}()
+	}
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// sleeperDeadlineContext is the parent of the context of Deadline.Context
+// for a Sleeper. It is done with the error of its own parent, or with
+// context.DeadlineExceeded like the contexts of context.WithDeadline, which
+// the standard contexts below it take over.
+type sleeperDeadlineContext struct {
+	context.Context // the parent, for Deadline and Value
+	done            chan struct{}
+	mu              sync.Mutex
+	err             error
+}
+
+func (c *sleeperDeadlineContext) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *sleeperDeadlineContext) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// finish makes c done with err, unless it already is.
+func (c *sleeperDeadlineContext) finish(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		c.err = err
+		close(c.done)
+	}
+}
+
+// String returns the instant of the deadline in ISO8601 in UTC, with as many
+// fractional digits as needed, such as "2024-02-14T08:15:00.5Z".
+func (d Deadline) String() string {
+	return FormatISO8601DateTime(d.at.UTC(), FormatOptions{Precision: PrecisionTrimmed})
+}
+
+// ParseDeadline parses the deadline of an ISO8601 date time with a UTC offset,
+// such as formatted by Deadline.String, on the time of tp.
+func ParseDeadline(tp TimeProvider, s string) (Deadline, error) {
+	at, err := ParseISO8601DateTime(s)
+	if err != nil {
+		return Deadline{}, err
+	}
+	return NewDeadline(tp, at), nil
+}
+
+// DeadlineToProtoTimestamp returns the instant of the deadline as a
+// google.protobuf.Timestamp.
+func DeadlineToProtoTimestamp(d Deadline) *tspb.Timestamp {
+	return tspb.New(d.at)
+}
+
+// ProtoTimestampToDeadline returns the deadline at a google.protobuf.Timestamp,
+// on the time of tp.
+func ProtoTimestampToDeadline(tp TimeProvider, t *tspb.Timestamp) (Deadline, error) {
+	if t == nil {
+		return Deadline{}, fmt.Errorf("%w: timestamp parameter not set", ErrInvalidValue)
+	}
+	if err := t.CheckValid(); err != nil {
+		return Deadline{}, fmt.Errorf("%w: %v", ErrInvalidValue, err)
+	}
+	return NewDeadline(tp, t.AsTime()), nil
+}
+
+// TTL is a deadline renewed by Touch, a time to live after the last use, such
+// as of an idle session, or a booking hold kept while the patient is active.
+// Once expired it stays expired. It is safe for concurrent use.
+type TTL struct {
+	tp       TimeProvider
+	ttl      time.Duration
+	mu       sync.Mutex
+	deadline Deadline
+}
+
+// NewTTL returns a TTL of ttl from the current time of tp.
+func NewTTL(tp TimeProvider, ttl time.Duration) *TTL {
+	return &TTL{tp: tp, ttl: ttl, deadline: DeadlineAfter(tp, ttl)}
+}
+
+// Duration returns the time to live after each Touch.
+func (t *TTL) Duration() time.Duration {
+	return t.ttl
+}
+
+// Deadline returns the current deadline.
+func (t *TTL) Deadline() Deadline {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.deadline
+}
+
+func (t *TTL) Expired() bool {
+	return t.Deadline().Expired()
+}
+
+func (t *TTL) Remaining() time.Duration {
+	return t.Deadline().Remaining()
+}
+
+// Touch renews the deadline to the time to live from now, unless expired, and
+// returns the deadline and true if renewed.
+func (t *TTL) Touch() (Deadline, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.deadline.Expired() {
+		return t.deadline, false
+	}
+	if renewed := DeadlineAfter(t.tp, t.ttl); renewed.at.After(t.deadline.at) {
+		t.deadline = renewed
+	}
+	return t.deadline, true
+}
+
+// Extend moves the deadline later by ext, unless expired, and returns the
+// deadline and true if extended.
+func (t *TTL) Extend(ext time.Duration) (Deadline, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.deadline.Expired() {
+		return t.deadline, false
+	}
+	t.deadline = t.deadline.Extend(ext)
+	return t.deadline, true
+}
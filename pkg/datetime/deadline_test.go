@@ -0,0 +1,158 @@
+package datetime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tspb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func Test_Deadline(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewManualTimeProvider(decoratorsNow)
+	d := DeadlineAfter(tp, 10*time.Minute)
+	_require.Equal(decoratorsNow.Add(10*time.Minute), d.At())
+	_require.False(d.Expired())
+	_require.Equal(10*time.Minute, d.Remaining())
+
+	// Expired from the instant on
+	tp.Advance(10*time.Minute - time.Nanosecond)
+	_require.False(d.Expired())
+	_require.Equal(time.Nanosecond, d.Remaining())
+	tp.Advance(time.Nanosecond)
+	_require.True(d.Expired())
+	_require.Equal(time.Duration(0), d.Remaining())
+	tp.Advance(time.Hour)
+	_require.Equal(time.Duration(0), d.Remaining())
+
+	// Immutable
+	extended := d.Extend(2 * time.Hour)
+	_require.True(d.Expired())
+	_require.False(extended.Expired())
+	_require.Equal(time.Hour, extended.Remaining())
+	_require.True(d.Before(extended))
+	_require.False(extended.Before(d))
+	_require.True(d.Extend(-time.Hour).Before(d))
+
+	_require.Equal(NewDeadline(tp, d.At()), d)
+}
+
+func Test_Deadline_Context(t *testing.T) {
+	_require := require.New(t)
+
+	// On the real clock
+	d := DeadlineAfter(Offset(NewUTCTimeProvider(), 24*time.Hour), 20*time.Millisecond)
+	ctx, cancel := d.Context(context.Background())
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	_require.True(ok)
+	_require.WithinDuration(time.Now().Add(20*time.Millisecond), deadline, 10*time.Millisecond)
+	<-ctx.Done()
+	_require.ErrorIs(ctx.Err(), context.DeadlineExceeded)
+
+	// On a manual clock
+	tp := NewManualTimeProvider(decoratorsNow)
+	ctx, cancel = DeadlineAfter(tp, time.Hour).Context(context.Background())
+	defer cancel()
+	_, ok = ctx.Deadline()
+	_require.False(ok)
+	_require.Eventually(func() bool { return tp.Sleepers() == 1 }, time.Second, time.Millisecond)
+	_require.Nil(ctx.Err())
+	child, cancelChild := context.WithCancel(ctx)
+	defer cancelChild()
+	tp.Advance(time.Hour)
+	<-ctx.Done()
+	_require.ErrorIs(ctx.Err(), context.DeadlineExceeded)
+	_require.ErrorIs(context.Cause(ctx), context.DeadlineExceeded)
+	<-child.Done()
+	_require.ErrorIs(child.Err(), context.DeadlineExceeded)
+
+	// Cancelled, cancelled by the parent, or already expired
+	ctx, cancel = DeadlineAfter(tp, time.Hour).Context(context.Background())
+	cancel()
+	_require.ErrorIs(ctx.Err(), context.Canceled)
+	_require.Eventually(func() bool { return tp.Sleepers() == 0 }, time.Second, time.Millisecond)
+
+	parent, cancelParent := context.WithCancel(context.Background())
+	ctx, cancel = DeadlineAfter(tp, time.Hour).Context(parent)
+	defer cancel()
+	cancelParent()
+	<-ctx.Done()
+	_require.ErrorIs(ctx.Err(), context.Canceled)
+
+	ctx, cancel = DeadlineAfter(tp, -time.Hour).Context(context.Background())
+	defer cancel()
+	_require.ErrorIs(ctx.Err(), context.DeadlineExceeded)
+
+	// On a frozen clock, after Remaining in real time
+	d = DeadlineAfter(NewFakeTimeProvider(decoratorsNow), 20*time.Millisecond)
+	ctx, cancel = d.Context(context.Background())
+	defer cancel()
+	<-ctx.Done()
+	_require.ErrorIs(ctx.Err(), context.DeadlineExceeded)
+	_require.False(d.Expired())
+}
+
+func Test_Deadline_Encoding(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewFakeTimeProvider(decoratorsNow)
+	stockholm, err := LoadLocation("Europe/Stockholm")
+	_require.Nil(err)
+	d := NewDeadline(tp, time.Date(2024, 2, 14, 9, 15, 0, 500000000, stockholm))
+
+	_require.Equal("2024-02-14T08:15:00.5Z", d.String())
+	parsed, err := ParseDeadline(tp, d.String())
+	_require.Nil(err)
+	_require.True(parsed.At().Equal(d.At()))
+	_require.Equal(d.Remaining(), parsed.Remaining())
+
+	_, err = ParseDeadline(tp, "tomorrow")
+	_require.ErrorIs(err, ErrInvalidValue)
+
+	ts := DeadlineToProtoTimestamp(d)
+	_require.Equal(&tspb.Timestamp{Seconds: d.At().Unix(), Nanos: 500000000}, ts)
+	parsed, err = ProtoTimestampToDeadline(tp, ts)
+	_require.Nil(err)
+	_require.True(parsed.At().Equal(d.At()))
+
+	_, err = ProtoTimestampToDeadline(tp, nil)
+	_require.ErrorIs(err, ErrInvalidValue)
+	_, err = ProtoTimestampToDeadline(tp, &tspb.Timestamp{Nanos: 1e9})
+	_require.ErrorIs(err, ErrInvalidValue)
+}
+
+func Test_TTL(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewManualTimeProvider(decoratorsNow)
+	ttl := NewTTL(tp, 15*time.Minute)
+	_require.Equal(15*time.Minute, ttl.Duration())
+	_require.Equal(15*time.Minute, ttl.Remaining())
+
+	tp.Advance(10 * time.Minute)
+	deadline, ok := ttl.Touch()
+	_require.True(ok)
+	_require.Equal(decoratorsNow.Add(25*time.Minute), deadline.At())
+	_require.Equal(15*time.Minute, ttl.Remaining())
+
+	// Extended past the TTL, a touch doesn't shorten it
+	deadline, ok = ttl.Extend(time.Hour)
+	_require.True(ok)
+	_require.Equal(decoratorsNow.Add(85*time.Minute), deadline.At())
+	deadline, _ = ttl.Touch()
+	_require.Equal(decoratorsNow.Add(85*time.Minute), deadline.At())
+
+	// Stays expired
+	tp.Advance(75 * time.Minute)
+	_require.True(ttl.Expired())
+	_, ok = ttl.Touch()
+	_require.False(ok)
+	_, ok = ttl.Extend(time.Hour)
+	_require.False(ok)
+	_require.True(ttl.Expired())
+	_require.Equal(decoratorsNow.Add(85*time.Minute), ttl.Deadline().At())
+}
@@ -0,0 +1,186 @@
+package datetime
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tspb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ErrClockOffset is returned by HLC.Update for a remote timestamp further
+// ahead of the local clock than HLC.MaxOffset.
+var ErrClockOffset = errors.New("clock offset too large")
+
+// HLCTimestamp is a timestamp of a hybrid logical clock, the wall time of the
+// clock and a logical counter that orders the timestamps of the same wall
+// time.
+type HLCTimestamp struct {
+	// Wall is the wall time, in UTC
+	Wall time.Time
+	// Logical orders the timestamps with the same wall time
+	Logical uint32
+}
+
+// Compare returns -1 if ts is before other, 0 if they are equal and +1 if ts
+// is after other.
+func (ts HLCTimestamp) Compare(other HLCTimestamp) int {
+	if c := ts.Wall.Compare(other.Wall); c != 0 {
+		return c
+	}
+	switch {
+	case ts.Logical < other.Logical:
+		return -1
+	case ts.Logical > other.Logical:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (ts HLCTimestamp) Before(other HLCTimestamp) bool {
+	return ts.Compare(other) < 0
+}
+
+func (ts HLCTimestamp) After(other HLCTimestamp) bool {
+	return ts.Compare(other) > 0
+}
+
+func (ts HLCTimestamp) IsZero() bool {
+	return ts.Wall.IsZero() && ts.Logical == 0
+}
+
+// String returns the compact encoding of ts, the nanoseconds since the Unix
+// epoch and the logical counter in fixed width hex, such as
+// "17b3c1d2e4f5a600-00000002". The encodings sort like the timestamps, for
+// the wall times from 1970 until 2262.
+func (ts HLCTimestamp) String() string {
+	return fmt.Sprintf("%016x-%08x", uint64(ts.Wall.UnixNano()), ts.Logical)
+}
+
+// ParseHLCTimestamp parses the compact encoding of HLCTimestamp.String.
+func ParseHLCTimestamp(s string) (HLCTimestamp, error) {
+	wall, logical, ok := strings.Cut(s, "-")
+	if !ok || len(wall) != 16 || len(logical) != 8 {
+		return HLCTimestamp{}, fmt.Errorf("%w: invalid HLC timestamp %q", ErrInvalidValue, s)
+	}
+	nanos, err := strconv.ParseUint(wall, 16, 64)
+	if err != nil || nanos > math.MaxInt64 {
+		return HLCTimestamp{}, fmt.Errorf("%w: invalid HLC timestamp %q", ErrInvalidValue, s)
+	}
+	counter, err := strconv.ParseUint(logical, 16, 32)
+	if err != nil {
+		return HLCTimestamp{}, fmt.Errorf("%w: invalid HLC timestamp %q", ErrInvalidValue, s)
+	}
+	return HLCTimestamp{Wall: time.Unix(0, int64(nanos)).UTC(), Logical: uint32(counter)}, nil
+}
+
+// HLCTimestampToProtoTimestamp returns the wall time of ts as a
+// google.protobuf.Timestamp, and its logical counter.
+func HLCTimestampToProtoTimestamp(ts HLCTimestamp) (*tspb.Timestamp, uint32) {
+	return tspb.New(ts.Wall), ts.Logical
+}
+
+// ProtoTimestampToHLCTimestamp returns the HLCTimestamp of a wall time as a
+// google.protobuf.Timestamp and a logical counter.
+func ProtoTimestampToHLCTimestamp(t *tspb.Timestamp, logical uint32) (HLCTimestamp, error) {
+	if t == nil {
+		return HLCTimestamp{}, fmt.Errorf("%w: timestamp parameter not set", ErrInvalidValue)
+	}
+	if err := t.CheckValid(); err != nil {
+		return HLCTimestamp{}, fmt.Errorf("%w: %v", ErrInvalidValue, err)
+	}
+	return HLCTimestamp{Wall: t.AsTime(), Logical: logical}, nil
+}
+
+// HLC is a hybrid logical clock, built on a TimeProvider. Its timestamps
+// follow the wall time of the TimeProvider, but are strictly increasing, also
+// when the clock is set back, and are after the timestamps of the received
+// events, also when the clock of the sender is ahead. So ordering events by
+// their timestamps orders them causally, even with clock skew between nodes.
+// It is safe for concurrent use.
+//
+//	ts := clock.Now() // timestamp of a local event, or of a sent event
+//	...
+//	ts, err := clock.Update(remote) // timestamp of a received event
+type HLC struct {
+	// MaxOffset is how far ahead of the TimeProvider the wall time of a
+	// received timestamp may be, unlimited if 0. Update fails with
+	// ErrClockOffset for timestamps further ahead, so that a single bad clock
+	// can't drag the wall time of all the nodes along.
+	MaxOffset time.Duration
+
+	tp   TimeProvider
+	mu   sync.Mutex
+	last HLCTimestamp
+}
+
+// NewHLC returns a hybrid logical clock on the time of tp.
+func NewHLC(tp TimeProvider) *HLC {
+	return &HLC{tp: tp}
+}
+
+// Now returns a timestamp after all the timestamps returned by c, for a local
+// or a sent event.
+func (c *HLC) Now() HLCTimestamp {
+	physical := c.physicalNow()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if physical.After(c.last.Wall) {
+		c.last = HLCTimestamp{Wall: physical}
+	} else {
+		c.last = c.last.next()
+	}
+	return c.last
+}
+
+// Update returns a timestamp after both all the timestamps returned by c and
+// remote, for a received event with the timestamp remote.
+func (c *HLC) Update(remote HLCTimestamp) (HLCTimestamp, error) {
+	physical := c.physicalNow()
+	remote.Wall = remote.Wall.UTC()
+	if c.MaxOffset > 0 && remote.Wall.Sub(physical) > c.MaxOffset {
+		return HLCTimestamp{}, fmt.Errorf("%w: remote timestamp %s is %s ahead of the clock", ErrClockOffset, remote, remote.Wall.Sub(physical))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch {
+	case physical.After(c.last.Wall) && physical.After(remote.Wall):
+		c.last = HLCTimestamp{Wall: physical}
+	case remote.Compare(c.last) > 0:
+		c.last = remote.next()
+	default:
+		c.last = c.last.next()
+	}
+	return c.last, nil
+}
+
+// Last returns the last timestamp returned by c, the zero timestamp if none.
+func (c *HLC) Last() HLCTimestamp {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.last
+}
+
+// physicalNow returns the time of the TimeProvider, in UTC and without a
+// monotonic clock reading, so that timestamps compare by wall time.
+func (c *HLC) physicalNow() time.Time {
+	return c.tp.Now().UTC()
+}
+
+// next returns the timestamp following ts, on the next nanosecond when the
+// logical counter is exhausted.
+func (ts HLCTimestamp) next() HLCTimestamp {
+	if ts.Logical == math.MaxUint32 {
+		return HLCTimestamp{Wall: ts.Wall.Add(time.Nanosecond)}
+	}
+	return HLCTimestamp{Wall: ts.Wall, Logical: ts.Logical + 1}
+}
@@ -0,0 +1,211 @@
+package datetime
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tspb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func Test_HLC(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewManualTimeProvider(decoratorsNow)
+	clock := NewHLC(tp)
+	_require.True(clock.Last().IsZero())
+
+	_require.Equal(HLCTimestamp{Wall: decoratorsNow}, clock.Now())
+	_require.Equal(HLCTimestamp{Wall: decoratorsNow, Logical: 1}, clock.Now())
+
+	// The clock is set back
+	tp.Set(decoratorsNow.Add(-time.Minute))
+	_require.Equal(HLCTimestamp{Wall: decoratorsNow, Logical: 2}, clock.Now())
+
+	// Caught up
+	tp.Set(decoratorsNow.Add(time.Second))
+	_require.Equal(HLCTimestamp{Wall: decoratorsNow.Add(time.Second)}, clock.Now())
+
+	// A remote clock ahead
+	remote := HLCTimestamp{Wall: decoratorsNow.Add(time.Minute), Logical: 5}
+	ts, err := clock.Update(remote)
+	_require.Nil(err)
+	_require.Equal(HLCTimestamp{Wall: remote.Wall, Logical: 6}, ts)
+	_require.Equal(HLCTimestamp{Wall: remote.Wall, Logical: 7}, clock.Now())
+
+	// A remote clock behind
+	ts, err = clock.Update(HLCTimestamp{Wall: decoratorsNow, Logical: 100})
+	_require.Nil(err)
+	_require.Equal(HLCTimestamp{Wall: remote.Wall, Logical: 8}, ts)
+
+	// The same wall time, the greater counter wins
+	ts, err = clock.Update(HLCTimestamp{Wall: remote.Wall, Logical: 100})
+	_require.Nil(err)
+	_require.Equal(HLCTimestamp{Wall: remote.Wall, Logical: 101}, ts)
+
+	// The physical clock ahead of both
+	tp.Set(decoratorsNow.Add(time.Hour))
+	ts, err = clock.Update(HLCTimestamp{Wall: decoratorsNow, Logical: 3})
+	_require.Nil(err)
+	_require.Equal(HLCTimestamp{Wall: tp.Now()}, ts)
+	_require.Equal(ts, clock.Last())
+
+	// In UTC, without a monotonic clock reading
+	ts = NewHLC(InLocation(NewUTCTimeProvider(), time.FixedZone("UTC+1", 3600))).Now()
+	_require.Equal(time.UTC, ts.Wall.Location())
+	_require.Equal(ts.Wall.Round(0), ts.Wall)
+}
+
+func Test_HLC_MaxOffset(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewFakeTimeProvider(decoratorsNow)
+	clock := NewHLC(tp)
+	clock.MaxOffset = 500 * time.Millisecond
+
+	ts, err := clock.Update(HLCTimestamp{Wall: decoratorsNow.Add(500 * time.Millisecond)})
+	_require.Nil(err)
+	_require.Equal(HLCTimestamp{Wall: decoratorsNow.Add(500 * time.Millisecond), Logical: 1}, ts)
+
+	_, err = clock.Update(HLCTimestamp{Wall: decoratorsNow.Add(time.Second)})
+	_require.ErrorIs(err, ErrClockOffset)
+	_require.Equal(ts, clock.Last())
+}
+
+func Test_HLC_LogicalOverflow(t *testing.T) {
+	_require := require.New(t)
+
+	clock := NewHLC(NewFakeTimeProvider(decoratorsNow))
+	ts, err := clock.Update(HLCTimestamp{Wall: decoratorsNow, Logical: math.MaxUint32 - 1})
+	_require.Nil(err)
+	_require.Equal(HLCTimestamp{Wall: decoratorsNow, Logical: math.MaxUint32}, ts)
+	_require.Equal(HLCTimestamp{Wall: decoratorsNow.Add(time.Nanosecond)}, clock.Now())
+}
+
+func Test_HLCTimestamp_Encoding(t *testing.T) {
+	_require := require.New(t)
+
+	ts := HLCTimestamp{Wall: decoratorsNow, Logical: 2}
+	_require.Equal("17b3abd18819cd15-00000002", ts.String())
+
+	parsed, err := ParseHLCTimestamp(ts.String())
+	_require.Nil(err)
+	_require.Equal(ts, parsed)
+
+	protoTS, logical := HLCTimestampToProtoTimestamp(ts)
+	_require.Equal(&tspb.Timestamp{Seconds: decoratorsNow.Unix(), Nanos: 123456789}, protoTS)
+	_require.Equal(uint32(2), logical)
+	parsed, err = ProtoTimestampToHLCTimestamp(protoTS, logical)
+	_require.Nil(err)
+	_require.Equal(ts, parsed)
+
+	// The encodings sort like the timestamps
+	timestamps := []HLCTimestamp{
+		{Wall: time.Unix(0, 0).UTC()},
+		{Wall: decoratorsNow},
+		{Wall: decoratorsNow, Logical: 1},
+		{Wall: decoratorsNow, Logical: 16},
+		{Wall: decoratorsNow.Add(time.Nanosecond)},
+		{Wall: time.Date(2262, 1, 1, 0, 0, 0, 0, time.UTC), Logical: math.MaxUint32},
+	}
+	encoded := make([]string, len(timestamps))
+	for i, ts := range timestamps {
+		encoded[i] = ts.String()
+		if i > 0 {
+			_require.True(timestamps[i-1].Before(ts))
+			_require.True(ts.After(timestamps[i-1]))
+		}
+	}
+	_require.True(sort.StringsAreSorted(encoded))
+
+	for _, s := range []string{"", "17b3abd18819cd15", "17b3abd18819cd15-2", "17b3abd18819cd1x-00000002", "87b3abd18819cd15-00000002", "17b3abd18819cd15-0000000g"} {
+		_, err := ParseHLCTimestamp(s)
+		_require.ErrorIs(err, ErrInvalidValue, s)
+	}
+
+	_, err = ProtoTimestampToHLCTimestamp(nil, 0)
+	_require.ErrorIs(err, ErrInvalidValue)
+	_, err = ProtoTimestampToHLCTimestamp(&tspb.Timestamp{Nanos: -1}, 0)
+	_require.ErrorIs(err, ErrInvalidValue)
+}
+
+func Test_HLC_Skew(t *testing.T) {
+	_require := require.New(t)
+
+	type message struct {
+		from string
+		ts   HLCTimestamp
+	}
+	type event struct {
+		ts    HLCTimestamp
+		cause *HLCTimestamp // the timestamp of the received message
+	}
+
+	// Nodes with skewed and jittering clocks, exchanging messages
+	base := NewManualTimeProvider(decoratorsNow)
+	skews := []time.Duration{-2 * time.Second, 0, 3 * time.Second}
+	maxSkew := 3*time.Second + 10*time.Millisecond
+
+	inboxes := make([]chan message, len(skews))
+	for i := range inboxes {
+		inboxes[i] = make(chan message, 1000)
+	}
+	events := make([][]event, len(skews))
+
+	var wg sync.WaitGroup
+	for i, skew := range skews {
+		wg.Add(1)
+		go func(i int, skew time.Duration) {
+			defer wg.Done()
+			r := rand.New(rand.NewPCG(uint64(i), 1))
+			clock := NewHLC(Offset(Skewed(base, 10*time.Millisecond, r), skew))
+			for n := 0; n < 500; n++ {
+				base.Advance(time.Microsecond)
+				switch r.IntN(3) {
+				case 0:
+					events[i] = append(events[i], event{ts: clock.Now()})
+				case 1:
+					ts := clock.Now()
+					events[i] = append(events[i], event{ts: ts})
+					to := (i + 1 + r.IntN(len(skews)-1)) % len(skews)
+					inboxes[to] <- message{from: fmt.Sprint(i), ts: ts}
+				case 2:
+					select {
+					case m := <-inboxes[i]:
+						ts, err := clock.Update(m.ts)
+						if err != nil {
+							t.Error(err)
+							return
+						}
+						events[i] = append(events[i], event{ts: ts, cause: &m.ts})
+					default:
+					}
+				}
+			}
+		}(i, skew)
+	}
+	wg.Wait()
+
+	received := 0
+	for i := range events {
+		for k, e := range events[i] {
+			// Strictly increasing on each node, despite the jitter
+			if k > 0 {
+				_require.True(e.ts.After(events[i][k-1].ts), "node %d event %d", i, k)
+			}
+			// After the cause
+			if e.cause != nil {
+				_require.True(e.ts.After(*e.cause))
+				received++
+			}
+			// Not further ahead of the physical clocks than the skew between them
+			_require.Less(e.ts.Wall.Sub(base.Now()), maxSkew)
+		}
+	}
+	_require.Greater(received, 0)
+}
@@ -0,0 +1,114 @@
+package datetime
+
+import (
+	"strconv"
+	"time"
+)
+
+// Default thresholds of HumanizeOptions.
+const (
+	DefaultHumanizeJustNow  = 45 * time.Second
+	DefaultHumanizeMinutes  = 45 * time.Minute
+	DefaultHumanizeHours    = 6 * time.Hour
+	DefaultHumanizeWeekdays = 6
+)
+
+// HumanizeOptions controls HumanizeWithOptions, zero values are replaced by
+// the defaults.
+type HumanizeOptions struct {
+	// Location decides the calendar days, the location of t if nil
+	Location *time.Location
+	// JustNow is the distance below which t is "just now"
+	JustNow time.Duration
+	// Minutes is the distance below which t is "in N minutes"/"N minutes ago"
+	Minutes time.Duration
+	// Hours is the distance below which t is "in N hours"/"N hours ago"
+	Hours time.Duration
+	// Weekdays is the number of calendar days within which t is "on Monday"/
+	// "last Monday", beyond that the date is used
+	Weekdays int
+	// DateStyle is the style of dates, beyond Weekdays, and of the time of
+	// "today"/"tomorrow"/"yesterday"
+	DateStyle Style
+}
+
+func (o HumanizeOptions) withDefaults(t time.Time) HumanizeOptions {
+	if o.Location == nil {
+		o.Location = t.Location()
+	}
+	if o.JustNow == 0 {
+		o.JustNow = DefaultHumanizeJustNow
+	}
+	if o.Minutes == 0 {
+		o.Minutes = DefaultHumanizeMinutes
+	}
+	if o.Hours == 0 {
+		o.Hours = DefaultHumanizeHours
+	}
+	if o.Weekdays == 0 {
+		o.Weekdays = DefaultHumanizeWeekdays
+	}
+	return o
+}
+
+// Humanize describes t relative to the current time of the TimeProvider, such
+// as "just now", "in 5 minutes", "3 hours ago", "tomorrow at 09:00",
+// "last Monday" or a date further away, for a locale such as "sv-SE".
+func Humanize(t time.Time, tp TimeProvider, locale string) (string, error) {
+	return HumanizeWithOptions(t, tp, locale, HumanizeOptions{})
+}
+
+// HumanizeWithOptions is Humanize with custom thresholds.
+func HumanizeWithOptions(t time.Time, tp TimeProvider, locale string, opts HumanizeOptions) (string, error) {
+	l, err := lookupLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	opts = opts.withDefaults(t)
+
+	now := tp.Now()
+	distance := t.Sub(now)
+	future := distance > 0
+	if distance < 0 {
+		distance = -distance
+	}
+
+	switch {
+	case distance < opts.JustNow:
+		return l.Humanize.JustNow, nil
+	case distance < opts.Minutes:
+		return l.humanizeCount(l.Humanize.Minutes, distance, time.Minute, future), nil
+	case distance < opts.Hours:
+		return l.humanizeCount(l.Humanize.Hours, distance, time.Hour, future), nil
+	}
+
+	t, now = t.In(opts.Location), now.In(opts.Location)
+	days := daysBetween(DateOf(now), DateOf(t))
+	switch {
+	case days >= -1 && days <= 1:
+		return FormatLocalizedRelative(t, now, opts.Location, locale, opts.DateStyle)
+	case days >= -opts.Weekdays && days <= opts.Weekdays:
+		pattern := l.Humanize.WeekdayPast
+		if future {
+			pattern = l.Humanize.WeekdayFuture
+		}
+		return l.expand(pattern, map[string]string{"weekday": l.Weekdays[t.Weekday()]}), nil
+	default:
+		return FormatLocalizedDate(DateOf(t), locale, opts.DateStyle)
+	}
+}
+
+// humanizeCount formats the distance rounded to the unit, at least 1.
+func (l *locale) humanizeCount(counts localeCounts, distance, unit time.Duration, future bool) string {
+	n := int(distance.Round(unit) / unit)
+	if n < 1 {
+		n = 1
+	}
+	return l.expand(counts.pattern(n, future), map[string]string{"n": strconv.Itoa(n)})
+}
+
+// daysBetween returns the number of calendar days from the date from to the
+// date to.
+func daysBetween(from, to Date) int {
+	return int(to.In(time.UTC).Sub(from.In(time.UTC)) / (24 * time.Hour))
+}
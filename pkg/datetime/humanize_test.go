@@ -0,0 +1,120 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Humanize(t *testing.T) {
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	require.Nil(t, err)
+
+	// Wednesday 2024-02-14 12:00 in Stockholm
+	now := time.Date(2024, 2, 14, 12, 0, 0, 0, stockholm)
+	tp := NewFakeTimeProvider(now.UTC())
+
+	for _, test := range []struct {
+		t      time.Time
+		sv, en string
+	}{
+		{now.Add(-10 * time.Second), "just nu", "just now"},
+		{now.Add(30 * time.Second), "just nu", "just now"},
+		{now.Add(-1 * time.Minute), "för 1 minut sedan", "1 minute ago"},
+		{now.Add(-89 * time.Second), "för 1 minut sedan", "1 minute ago"},
+		{now.Add(-90 * time.Second), "för 2 minuter sedan", "2 minutes ago"},
+		{now.Add(44 * time.Minute), "om 44 minuter", "in 44 minutes"},
+		{now.Add(45 * time.Minute), "om 1 timme", "in 1 hour"},
+		{now.Add(-3 * time.Hour), "för 3 timmar sedan", "3 hours ago"},
+		{now.Add(6 * time.Hour), "i dag kl. 18:00", "today at 18:00"},
+		{now.Add(21 * time.Hour), "i morgon kl. 09:00", "tomorrow at 09:00"},
+		{now.Add(-15 * time.Hour), "i går kl. 21:00", "yesterday at 21:00"},
+		{now.Add(2 * 24 * time.Hour), "på fredag", "on Friday"},
+		{now.Add(-2 * 24 * time.Hour), "i måndags", "last Monday"},
+		{now.Add(6 * 24 * time.Hour), "på tisdag", "on Tuesday"},
+		{now.Add(7 * 24 * time.Hour), "21 februari 2024", "21 February 2024"},
+		{now.Add(-30 * 24 * time.Hour), "15 januari 2024", "15 January 2024"},
+	} {
+		t.Run(test.en, func(t *testing.T) {
+			humanized, err := HumanizeWithOptions(test.t.UTC(), tp, "sv", HumanizeOptions{Location: stockholm, DateStyle: StyleLong})
+			require.Nil(t, err)
+			require.Equal(t, test.sv, humanized)
+
+			humanized, err = HumanizeWithOptions(test.t.UTC(), tp, "en", HumanizeOptions{Location: stockholm, DateStyle: StyleLong})
+			require.Nil(t, err)
+			require.Equal(t, test.en, humanized)
+		})
+	}
+}
+
+func Test_Humanize_Locales(t *testing.T) {
+	now := time.Date(2024, 2, 14, 12, 0, 0, 0, time.UTC)
+	tp := NewFakeTimeProvider(now)
+
+	for _, test := range []struct {
+		locale                    string
+		justNow, past, in, monday string
+	}{
+		{"nb", "akkurat nå", "for 2 timer siden", "om 1 minutt", "forrige mandag"},
+		{"da", "lige nu", "for 2 timer siden", "om 1 minut", "i mandags"},
+		{"fi", "juuri nyt", "2 tuntia sitten", "1 minuutin päästä", "viime maanantaina"},
+		{"en-US", "just now", "2 hours ago", "in 1 minute", "last Monday"},
+	} {
+		t.Run(test.locale, func(t *testing.T) {
+			for tm, expected := range map[time.Time]string{
+				now:                          test.justNow,
+				now.Add(-2 * time.Hour):      test.past,
+				now.Add(time.Minute):         test.in,
+				now.Add(-2 * 24 * time.Hour): test.monday,
+			} {
+				humanized, err := Humanize(tm, tp, test.locale)
+				require.Nil(t, err)
+				require.Equal(t, expected, humanized)
+			}
+		})
+	}
+
+	_, err := Humanize(now, tp, "xx")
+	require.ErrorIs(t, err, ErrInvalidValue)
+}
+
+func Test_Humanize_Thresholds(t *testing.T) {
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	require.Nil(t, err)
+
+	// Late in the evening, so that an hour later is tomorrow
+	now := time.Date(2024, 2, 14, 23, 30, 0, 0, stockholm)
+	tp := NewFakeTimeProvider(now)
+
+	humanized, err := Humanize(now.Add(time.Hour), tp, "en")
+	require.Nil(t, err)
+	require.Equal(t, "in 1 hour", humanized)
+
+	opts := HumanizeOptions{Hours: time.Hour, Minutes: 10 * time.Minute, JustNow: 5 * time.Second, Weekdays: 2}
+
+	humanized, err = HumanizeWithOptions(now.Add(time.Hour), tp, "en", opts)
+	require.Nil(t, err)
+	require.Equal(t, "tomorrow at 00:30", humanized)
+
+	humanized, err = HumanizeWithOptions(now.Add(-30*time.Minute), tp, "en", opts)
+	require.Nil(t, err)
+	require.Equal(t, "1 hour ago", humanized)
+
+	humanized, err = HumanizeWithOptions(now.Add(-90*time.Minute), tp, "en", opts)
+	require.Nil(t, err)
+	require.Equal(t, "today at 22:00", humanized)
+
+	humanized, err = HumanizeWithOptions(now.Add(-10*time.Second), tp, "en", opts)
+	require.Nil(t, err)
+	require.Equal(t, "1 minute ago", humanized)
+
+	humanized, err = HumanizeWithOptions(now.Add(3*24*time.Hour), tp, "en", opts)
+	require.Nil(t, err)
+	require.Equal(t, "17/02/2024", humanized)
+
+	// The calendar days are decided by the location
+	humanized, err = HumanizeWithOptions(now.Add(time.Hour), tp, "en", HumanizeOptions{Hours: time.Minute, Location: time.UTC})
+	require.Nil(t, err)
+	require.Equal(t, "today at 23:30", humanized)
+}
@@ -0,0 +1,237 @@
+package datetime
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// IntervalSet is a set of instants kept as sorted, disjoint and coalesced
+// half-open intervals, such as the free or busy time of a chair, a dentist or
+// a room. The zero value is an empty set.
+//
+// Add and Remove modify the set, while Union, Intersect, Subtract and
+// Complement return a new set.
+type IntervalSet struct {
+	// Sorted by Start, not empty, and neither overlapping nor touching
+	intervals []Interval
+}
+
+// NewIntervalSet returns the set of the intervals, in any order. Empty
+// intervals are ignored.
+func NewIntervalSet(intervals ...Interval) *IntervalSet {
+	s := &IntervalSet{intervals: make([]Interval, 0, len(intervals))}
+	for _, i := range intervals {
+		if !i.IsEmpty() {
+			s.intervals = append(s.intervals, i)
+		}
+	}
+	sort.Slice(s.intervals, func(i, j int) bool { return s.intervals[i].Start.Before(s.intervals[j].Start) })
+
+	n := 0
+	for _, i := range s.intervals {
+		if n > 0 && !i.Start.After(s.intervals[n-1].End) {
+			if i.End.After(s.intervals[n-1].End) {
+				s.intervals[n-1].End = i.End
+			}
+			continue
+		}
+		s.intervals[n] = i
+		n++
+	}
+	s.intervals = s.intervals[:n]
+	return s
+}
+
+// Len returns the number of disjoint intervals of s.
+func (s *IntervalSet) Len() int {
+	return len(s.intervals)
+}
+
+// IsEmpty reports whether s contains no time at all.
+func (s *IntervalSet) IsEmpty() bool {
+	return len(s.intervals) == 0
+}
+
+// Intervals returns a copy of the sorted, disjoint intervals of s.
+func (s *IntervalSet) Intervals() []Interval {
+	return append([]Interval(nil), s.intervals...)
+}
+
+// Each calls yield for each of the sorted, disjoint intervals of s, until
+// yield returns false.
+func (s *IntervalSet) Each(yield func(Interval) bool) {
+	for _, i := range s.intervals {
+		if !yield(i) {
+			return
+		}
+	}
+}
+
+// String returns the intervals of s as "{start/end, start/end}".
+func (s *IntervalSet) String() string {
+	formatted := make([]string, 0, len(s.intervals))
+	for _, i := range s.intervals {
+		formatted = append(formatted, i.String())
+	}
+	return "{" + strings.Join(formatted, ", ") + "}"
+}
+
+// TotalDuration returns the sum of the durations of the intervals of s.
+func (s *IntervalSet) TotalDuration() time.Duration {
+	var total time.Duration
+	for _, i := range s.intervals {
+		total += i.Duration()
+	}
+	return total
+}
+
+// Contains reports whether t is within any interval of s, in O(log n).
+func (s *IntervalSet) Contains(t time.Time) bool {
+	// The first interval ending after t is the only candidate
+	n := sort.Search(len(s.intervals), func(k int) bool { return s.intervals[k].End.After(t) })
+	return n < len(s.intervals) && s.intervals[n].Contains(t)
+}
+
+// ContainsInterval reports whether all of i is within s, in O(log n).
+func (s *IntervalSet) ContainsInterval(i Interval) bool {
+	if i.IsEmpty() {
+		return true
+	}
+	n := sort.Search(len(s.intervals), func(k int) bool { return s.intervals[k].End.After(i.Start) })
+	return n < len(s.intervals) && !s.intervals[n].Start.After(i.Start) && !s.intervals[n].End.Before(i.End)
+}
+
+// Overlaps reports whether any of i is within s, in O(log n).
+func (s *IntervalSet) Overlaps(i Interval) bool {
+	n := sort.Search(len(s.intervals), func(k int) bool { return s.intervals[k].End.After(i.Start) })
+	return n < len(s.intervals) && s.intervals[n].Overlaps(i)
+}
+
+// FirstFit returns the first interval of length d that is within s, starting
+// at the start of the first interval of s that is long enough.
+func (s *IntervalSet) FirstFit(d time.Duration) (Interval, bool) {
+	for _, i := range s.intervals {
+		if i.Duration() >= d {
+			return Interval{Start: i.Start, End: i.Start.Add(d)}, true
+		}
+	}
+	return Interval{}, false
+}
+
+// Add adds i to s, coalescing it with the intervals it overlaps or touches.
+func (s *IntervalSet) Add(i Interval) {
+	if i.IsEmpty() {
+		return
+	}
+	// The intervals [lo, hi) overlap or touch i
+	lo := sort.Search(len(s.intervals), func(k int) bool { return !s.intervals[k].End.Before(i.Start) })
+	hi := sort.Search(len(s.intervals), func(k int) bool { return s.intervals[k].Start.After(i.End) })
+	if lo < hi {
+		if s.intervals[lo].Start.Before(i.Start) {
+			i.Start = s.intervals[lo].Start
+		}
+		if s.intervals[hi-1].End.After(i.End) {
+			i.End = s.intervals[hi-1].End
+		}
+	}
+	s.replace(lo, hi, i)
+}
+
+// Remove removes i from s, splitting an interval that contains i.
+func (s *IntervalSet) Remove(i Interval) {
+	if i.IsEmpty() {
+		return
+	}
+	// The intervals [lo, hi) overlap i
+	lo := sort.Search(len(s.intervals), func(k int) bool { return s.intervals[k].End.After(i.Start) })
+	hi := sort.Search(len(s.intervals), func(k int) bool { return !s.intervals[k].Start.Before(i.End) })
+	if lo >= hi {
+		return
+	}
+
+	var rest []Interval
+	if first := s.intervals[lo]; first.Start.Before(i.Start) {
+		rest = append(rest, Interval{Start: first.Start, End: i.Start})
+	}
+	if last := s.intervals[hi-1]; last.End.After(i.End) {
+		rest = append(rest, Interval{Start: i.End, End: last.End})
+	}
+	s.replace(lo, hi, rest...)
+}
+
+// replace replaces the intervals [lo, hi) of s with the intervals.
+func (s *IntervalSet) replace(lo, hi int, intervals ...Interval) {
+	replaced := make([]Interval, 0, len(s.intervals)-(hi-lo)+len(intervals))
+	replaced = append(replaced, s.intervals[:lo]...)
+	replaced = append(replaced, intervals...)
+	replaced = append(replaced, s.intervals[hi:]...)
+	s.intervals = replaced
+}
+
+// Union returns the set of the instants within s or other.
+func (s *IntervalSet) Union(other *IntervalSet) *IntervalSet {
+	all := make([]Interval, 0, len(s.intervals)+len(other.intervals))
+	all = append(all, s.intervals...)
+	all = append(all, other.intervals...)
+	return NewIntervalSet(all...)
+}
+
+// Intersect returns the set of the instants within both s and other.
+func (s *IntervalSet) Intersect(other *IntervalSet) *IntervalSet {
+	result := &IntervalSet{}
+	a, b := s.intervals, other.intervals
+	for len(a) > 0 && len(b) > 0 {
+		start, end := a[0].Start, a[0].End
+		if b[0].Start.After(start) {
+			start = b[0].Start
+		}
+		if b[0].End.Before(end) {
+			end = b[0].End
+		}
+		if start.Before(end) {
+			result.intervals = append(result.intervals, Interval{Start: start, End: end})
+		}
+		// Move on from the interval ending first, the other may overlap more
+		if a[0].End.Before(b[0].End) {
+			a = a[1:]
+		} else {
+			b = b[1:]
+		}
+	}
+	return result
+}
+
+// Subtract returns the set of the instants within s but not within other.
+func (s *IntervalSet) Subtract(other *IntervalSet) *IntervalSet {
+	result := &IntervalSet{}
+	b := other.intervals
+	for _, i := range s.intervals {
+		// Skip the intervals of other ending before i
+		for len(b) > 0 && !b[0].End.After(i.Start) {
+			b = b[1:]
+		}
+		start := i.Start
+		for _, cut := range b {
+			if !cut.Start.Before(i.End) {
+				break
+			}
+			if cut.Start.After(start) {
+				result.intervals = append(result.intervals, Interval{Start: start, End: cut.Start})
+			}
+			if cut.End.After(start) {
+				start = cut.End
+			}
+		}
+		if start.Before(i.End) {
+			result.intervals = append(result.intervals, Interval{Start: start, End: i.End})
+		}
+	}
+	return result
+}
+
+// Complement returns the set of the instants within the interval within but
+// not within s, such as the free time of a day given the busy time.
+func (s *IntervalSet) Complement(within Interval) *IntervalSet {
+	return NewIntervalSet(within).Subtract(s)
+}
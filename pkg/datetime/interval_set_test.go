@@ -0,0 +1,161 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var intervalSetBase = time.Date(2024, 2, 14, 0, 0, 0, 0, time.UTC)
+
+// hours returns the interval [from, to) in hours since intervalSetBase.
+func hours(from, to int) Interval {
+	return NewInterval(intervalSetBase.Add(time.Duration(from)*time.Hour), intervalSetBase.Add(time.Duration(to)*time.Hour))
+}
+
+func Test_IntervalSet(t *testing.T) {
+	_require := require.New(t)
+
+	s := NewIntervalSet(hours(10, 12), hours(8, 9), hours(9, 10), hours(14, 15), hours(16, 16))
+	_require.Equal([]Interval{hours(8, 12), hours(14, 15)}, s.Intervals())
+	_require.Equal(2, s.Len())
+	_require.Equal(5*time.Hour, s.TotalDuration())
+
+	_require.True(s.Contains(intervalSetBase.Add(8 * time.Hour)))
+	_require.False(s.Contains(intervalSetBase.Add(12 * time.Hour)))
+	_require.True(s.ContainsInterval(hours(9, 12)))
+	_require.False(s.ContainsInterval(hours(11, 14)))
+	_require.True(s.Overlaps(hours(11, 14)))
+	_require.False(s.Overlaps(hours(12, 14)))
+
+	s.Add(hours(12, 13))
+	_require.Equal([]Interval{hours(8, 13), hours(14, 15)}, s.Intervals())
+	s.Add(hours(13, 14))
+	_require.Equal([]Interval{hours(8, 15)}, s.Intervals())
+	s.Remove(hours(10, 11))
+	_require.Equal([]Interval{hours(8, 10), hours(11, 15)}, s.Intervals())
+	s.Remove(hours(7, 12))
+	_require.Equal([]Interval{hours(12, 15)}, s.Intervals())
+
+	fit, ok := NewIntervalSet(hours(8, 9), hours(10, 13)).FirstFit(2 * time.Hour)
+	_require.True(ok)
+	_require.Equal(hours(10, 12), fit)
+	_, ok = s.FirstFit(4 * time.Hour)
+	_require.False(ok)
+
+	_require.Equal("{2024-02-14T12:00:00Z/2024-02-14T15:00:00Z}", s.String())
+
+	var zero IntervalSet
+	_require.True(zero.IsEmpty())
+	zero.Add(hours(1, 2))
+	_require.Equal([]Interval{hours(1, 2)}, zero.Intervals())
+}
+
+func Test_IntervalSet_Algebra(t *testing.T) {
+	_require := require.New(t)
+
+	// The opening hours of a chair, and its booked appointments
+	open := NewIntervalSet(hours(8, 12), hours(13, 17))
+	booked := NewIntervalSet(hours(9, 10), hours(11, 14), hours(16, 18))
+
+	_require.Equal([]Interval{hours(8, 18)}, open.Union(booked).Intervals())
+	_require.Equal([]Interval{hours(9, 10), hours(11, 12), hours(13, 14), hours(16, 17)}, open.Intersect(booked).Intervals())
+	_require.Equal([]Interval{hours(8, 9), hours(10, 11), hours(14, 16)}, open.Subtract(booked).Intervals())
+	_require.Equal([]Interval{hours(6, 8), hours(12, 13), hours(17, 20)}, open.Complement(hours(6, 20)).Intervals())
+	_require.Equal([]Interval{hours(12, 13)}, open.Complement(hours(12, 13)).Intervals())
+}
+
+// intervalGrid is the naive reference of an IntervalSet, one bool per hour.
+type intervalGrid [64]bool
+
+func (g *intervalGrid) set(i Interval, value bool) {
+	for h := 0; h < len(g); h++ {
+		if i.Contains(intervalSetBase.Add(time.Duration(h) * time.Hour)) {
+			g[h] = value
+		}
+	}
+}
+
+func gridOf(s *IntervalSet) intervalGrid {
+	var g intervalGrid
+	for h := range g {
+		g[h] = s.Contains(intervalSetBase.Add(time.Duration(h) * time.Hour))
+	}
+	return g
+}
+
+func requireCoalesced(t *testing.T, s *IntervalSet) {
+	for k, i := range s.intervals {
+		require.False(t, i.IsEmpty(), "empty interval %s in %s", i, s)
+		if k > 0 {
+			require.True(t, s.intervals[k-1].End.Before(i.Start), "%s is not coalesced", s)
+		}
+	}
+}
+
+func FuzzIntervalSet(f *testing.F) {
+	f.Add([]byte{0, 1, 5, 1, 3, 9, 2, 4, 6, 3, 0, 2, 4, 8, 12})
+	f.Add([]byte{0, 10, 20, 0, 20, 30, 1, 15, 25, 5, 0, 40})
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		a, b := &IntervalSet{}, &IntervalSet{}
+		var gridA, gridB intervalGrid
+
+		for len(ops) >= 3 {
+			op, from, to := ops[0]%6, int(ops[1]%64), int(ops[2]%64)
+			ops = ops[3:]
+			i := hours(from, to)
+
+			var want intervalGrid
+			switch op {
+			case 0:
+				a.Add(i)
+				gridA.set(i, true)
+			case 1:
+				a.Remove(i)
+				gridA.set(i, false)
+			case 2:
+				b.Add(i)
+				gridB.set(i, true)
+			case 3:
+				for h := range want {
+					want[h] = gridA[h] || gridB[h]
+				}
+				requireCoalesced(t, a.Union(b))
+				require.Equal(t, want, gridOf(a.Union(b)))
+			case 4:
+				for h := range want {
+					want[h] = gridA[h] && gridB[h]
+				}
+				requireCoalesced(t, a.Intersect(b))
+				require.Equal(t, want, gridOf(a.Intersect(b)))
+			case 5:
+				for h := range want {
+					want[h] = gridA[h] && !gridB[h]
+				}
+				requireCoalesced(t, a.Subtract(b))
+				require.Equal(t, want, gridOf(a.Subtract(b)))
+
+				var within intervalGrid
+				within.set(i, true)
+				for h := range want {
+					want[h] = within[h] && !gridA[h]
+				}
+				require.Equal(t, want, gridOf(a.Complement(i)))
+			}
+
+			requireCoalesced(t, a)
+			require.Equal(t, gridA, gridOf(a))
+			require.Equal(t, gridB, gridOf(b))
+
+			var total time.Duration
+			for _, set := range gridA {
+				if set {
+					total += time.Hour
+				}
+			}
+			require.Equal(t, total, a.TotalDuration())
+		}
+	})
+}
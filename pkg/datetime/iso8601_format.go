@@ -0,0 +1,122 @@
+package datetime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Precision is the smallest unit of time included by FormatISO8601DateTime
+// and FormatISO8601TimeOfDay, finer units are truncated.
+type Precision int
+
+const (
+	// PrecisionSeconds formats "15:04:05", like ISO8601DateTime
+	PrecisionSeconds Precision = iota
+	// PrecisionMinutes formats "15:04"
+	PrecisionMinutes
+	// PrecisionMillis formats "15:04:05.000"
+	PrecisionMillis
+	// PrecisionMicros formats "15:04:05.000000"
+	PrecisionMicros
+	// PrecisionNanos formats "15:04:05.000000000"
+	PrecisionNanos
+	// PrecisionTrimmed formats as many fractional digits as needed, without
+	// trailing zeros, like time.RFC3339Nano
+	PrecisionTrimmed
+)
+
+// FormatOptions controls FormatISO8601DateTime and FormatISO8601TimeOfDay.
+// The zero value formats like TimeToISO8601DateTimeString.
+type FormatOptions struct {
+	Precision Precision
+	// NumericUTC formats a zero UTC offset as "+00:00" instead of "Z"
+	NumericUTC bool
+	// Basic uses the ISO8601 basic format without separators,
+	// "20060102T150405Z", instead of the extended format
+	Basic bool
+}
+
+// Layouts accepted by ParseISO8601DateTime and ParseISO8601TimeOfDay, a
+// fraction of a second is accepted after the seconds by time.Parse.
+var (
+	iso8601DateTimeLayouts = []string{
+		"2006-01-02T15:04:05Z07:00",
+		"2006-01-02T15:04Z07:00",
+		"20060102T150405Z0700",
+		"20060102T1504Z0700",
+	}
+	iso8601TimeOfDayLayouts = []string{
+		"15:04:05Z07:00",
+		"15:04Z07:00",
+		"150405Z0700",
+		"1504Z0700",
+	}
+)
+
+// FormatISO8601DateTime formats t as an ISO8601 date time with its UTC offset.
+func FormatISO8601DateTime(t time.Time, opts FormatOptions) string {
+	if opts.Basic {
+		return t.Format("20060102T" + timeOfDayLayout(opts))
+	}
+	return t.Format("2006-01-02T" + timeOfDayLayout(opts))
+}
+
+// FormatISO8601TimeOfDay formats the time of day of t as ISO8601 with its UTC
+// offset.
+func FormatISO8601TimeOfDay(t time.Time, opts FormatOptions) string {
+	return t.Format(timeOfDayLayout(opts))
+}
+
+func timeOfDayLayout(opts FormatOptions) string {
+	var b strings.Builder
+
+	b.WriteString("15:04")
+	if opts.Precision != PrecisionMinutes {
+		b.WriteString(":05")
+	}
+	switch opts.Precision {
+	case PrecisionMillis:
+		b.WriteString(".000")
+	case PrecisionMicros:
+		b.WriteString(".000000")
+	case PrecisionNanos:
+		b.WriteString(".000000000")
+	case PrecisionTrimmed:
+		b.WriteString(".999999999")
+	}
+
+	if opts.NumericUTC {
+		b.WriteString("-07:00")
+	} else {
+		b.WriteString("Z07:00")
+	}
+
+	if opts.Basic {
+		return strings.ReplaceAll(b.String(), ":", "")
+	}
+	return b.String()
+}
+
+// ParseISO8601DateTime parses an ISO8601 date time with a UTC offset in any of
+// the formats produced by FormatISO8601DateTime.
+func ParseISO8601DateTime(s string) (time.Time, error) {
+	for _, layout := range iso8601DateTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q is not an ISO8601 date time", ErrInvalidValue, s)
+}
+
+// ParseISO8601TimeOfDay parses an ISO8601 time of day with a UTC offset in any
+// of the formats produced by FormatISO8601TimeOfDay. Like
+// ISO8601TimeOfDayStringToTime, the date of the result is 1970-01-01.
+func ParseISO8601TimeOfDay(s string) (time.Time, error) {
+	for _, layout := range iso8601TimeOfDayLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.AddDate(1970, 0, 0), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q is not an ISO8601 time of day", ErrInvalidValue, s)
+}
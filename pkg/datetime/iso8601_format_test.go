@@ -0,0 +1,88 @@
+package datetime
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FormatISO8601DateTime(t *testing.T) {
+	utcTime := time.Date(2024, 2, 14, 8, 5, 9, 123450000, time.UTC)
+	localTime := utcTime.In(time.FixedZone("", 3600))
+
+	for _, test := range []struct {
+		opts            FormatOptions
+		utc, local, tod string
+	}{
+		{FormatOptions{}, "2024-02-14T08:05:09Z", "2024-02-14T09:05:09+01:00", "09:05:09+01:00"},
+		{FormatOptions{Precision: PrecisionMinutes}, "2024-02-14T08:05Z", "2024-02-14T09:05+01:00", "09:05+01:00"},
+		{FormatOptions{Precision: PrecisionMillis}, "2024-02-14T08:05:09.123Z", "2024-02-14T09:05:09.123+01:00", "09:05:09.123+01:00"},
+		{FormatOptions{Precision: PrecisionMicros}, "2024-02-14T08:05:09.123450Z", "2024-02-14T09:05:09.123450+01:00", "09:05:09.123450+01:00"},
+		{FormatOptions{Precision: PrecisionNanos}, "2024-02-14T08:05:09.123450000Z", "2024-02-14T09:05:09.123450000+01:00", "09:05:09.123450000+01:00"},
+		{FormatOptions{Precision: PrecisionTrimmed}, "2024-02-14T08:05:09.12345Z", "2024-02-14T09:05:09.12345+01:00", "09:05:09.12345+01:00"},
+		{FormatOptions{NumericUTC: true}, "2024-02-14T08:05:09+00:00", "2024-02-14T09:05:09+01:00", "09:05:09+01:00"},
+		{FormatOptions{Basic: true}, "20240214T080509Z", "20240214T090509+0100", "090509+0100"},
+		{FormatOptions{Basic: true, Precision: PrecisionMinutes}, "20240214T0805Z", "20240214T0905+0100", "0905+0100"},
+		{FormatOptions{Basic: true, Precision: PrecisionMillis, NumericUTC: true}, "20240214T080509.123+0000", "20240214T090509.123+0100", "090509.123+0100"},
+	} {
+		t.Run(fmt.Sprintf("%+v", test.opts), func(t *testing.T) {
+			require.Equal(t, test.utc, FormatISO8601DateTime(utcTime, test.opts))
+			require.Equal(t, test.local, FormatISO8601DateTime(localTime, test.opts))
+			require.Equal(t, test.tod, FormatISO8601TimeOfDay(localTime, test.opts))
+		})
+	}
+
+	// The zero options format like TimeToISO8601DateTimeString
+	require.Equal(t, TimeToISO8601DateTimeString(localTime), FormatISO8601DateTime(localTime, FormatOptions{}))
+	require.Equal(t, TimeToISO8601TimeOfDayString(localTime), FormatISO8601TimeOfDay(localTime, FormatOptions{}))
+}
+
+func Test_ParseISO8601_RoundTrip(t *testing.T) {
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	require.Nil(t, err)
+
+	truncations := map[Precision]time.Duration{
+		PrecisionSeconds: time.Second,
+		PrecisionMinutes: time.Minute,
+		PrecisionMillis:  time.Millisecond,
+		PrecisionMicros:  time.Microsecond,
+		PrecisionNanos:   time.Nanosecond,
+		PrecisionTrimmed: time.Nanosecond,
+	}
+
+	for _, tm := range []time.Time{
+		time.Date(2024, 2, 14, 8, 5, 9, 123456789, time.UTC),
+		time.Date(2024, 7, 14, 23, 59, 59, 999999999, stockholm),
+		time.Date(2024, 2, 14, 8, 5, 0, 0, time.FixedZone("", -(5*3600+30*60))),
+	} {
+		for precision, truncation := range truncations {
+			for _, numericUTC := range []bool{false, true} {
+				for _, basic := range []bool{false, true} {
+					opts := FormatOptions{Precision: precision, NumericUTC: numericUTC, Basic: basic}
+					expected := tm.Truncate(truncation)
+
+					formatted := FormatISO8601DateTime(tm, opts)
+					parsed, err := ParseISO8601DateTime(formatted)
+					require.Nilf(t, err, formatted)
+					require.Truef(t, expected.Equal(parsed), "%s: %v != %v", formatted, expected, parsed)
+					_, expectedOffset := tm.Zone()
+					_, offset := parsed.Zone()
+					require.Equal(t, expectedOffset, offset, formatted)
+
+					formatted = FormatISO8601TimeOfDay(tm, opts)
+					parsed, err = ParseISO8601TimeOfDay(formatted)
+					require.Nilf(t, err, formatted)
+					require.Equal(t, TimeOfDayOf(expected), TimeOfDayOf(parsed), formatted)
+					require.Equal(t, 1970, parsed.Year())
+				}
+			}
+		}
+	}
+
+	_, err = ParseISO8601DateTime("2024-02-14")
+	require.ErrorIs(t, err, ErrInvalidValue)
+	_, err = ParseISO8601TimeOfDay("09")
+	require.ErrorIs(t, err, ErrInvalidValue)
+}
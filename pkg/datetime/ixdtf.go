@@ -0,0 +1,282 @@
+package datetime
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// IXDTFLayout is the layout of the RFC 3339 date time that starts an RFC 9557
+// (IXDTF) string, fractional seconds are only included when set.
+const IXDTFLayout = "2006-01-02T15:04:05.999999999Z07:00"
+
+// IXDTFCalendarKey is the suffix tag key of the calendar, "[u-ca=gregory]".
+const IXDTFCalendarKey = "u-ca"
+
+var (
+	ixdtfNumericZoneRegexp = regexp.MustCompile(`^[+-]\d{2}:?\d{2}$`)
+	ixdtfKeyRegexp         = regexp.MustCompile(`^[a-z_][a-z0-9_-]*$`)
+	ixdtfValueRegexp       = regexp.MustCompile(`^[A-Za-z0-9]+(-[A-Za-z0-9]+)*$`)
+)
+
+// Calendars that are compatible with the proleptic Gregorian calendar of Time.
+var ixdtfGregorianCalendars = map[string]bool{"iso8601": true, "gregory": true}
+
+// IXDTF is a parsed RFC 9557 (Internet Extended Date/Time Format) string,
+// such as "2024-02-14T09:00:00+01:00[Europe/Stockholm][u-ca=gregory]".
+type IXDTF struct {
+	// Time is the instant, in the location of Zone when there is one.
+	Time time.Time
+	// Zone is the bracketed IANA zone ID or numeric offset, "" if none.
+	Zone string
+	// ZoneCritical is set when the zone is flagged with "!", and the
+	// offset of the date time must not be in conflict with it.
+	ZoneCritical bool
+	// Calendar is the value of the first "u-ca" tag, "" if none.
+	Calendar string
+	// Tags are all suffix tags after the zone, including "u-ca", in order.
+	Tags []IXDTFTag
+}
+
+// IXDTFTag is a "[key=value]" suffix tag of an IXDTF string.
+type IXDTFTag struct {
+	Key      string
+	Value    string
+	Critical bool
+}
+
+// IXDTFOffsetConflict decides how an offset that is inconsistent with the
+// elective (not critical) zone is resolved, a critical zone always rejects it.
+type IXDTFOffsetConflict int
+
+const (
+	// IXDTFRejectConflict fails the parsing
+	IXDTFRejectConflict IXDTFOffsetConflict = iota
+	// IXDTFPreferOffset keeps the instant given by the offset
+	IXDTFPreferOffset
+	// IXDTFPreferZone keeps the wall clock time and resolves it in the zone
+	IXDTFPreferZone
+)
+
+// IXDTFOptions controls ParseIXDTFWithOptions.
+type IXDTFOptions struct {
+	OffsetConflict IXDTFOffsetConflict
+}
+
+// IXDTFError describes why an IXDTF string could not be parsed, it wraps
+// ErrInvalidValue.
+type IXDTFError struct {
+	Input  string
+	Pos    int // byte offset of the problem in Input
+	Reason string
+}
+
+func (e *IXDTFError) Error() string {
+	return fmt.Sprintf("%v: ixdtf: %s at position %d in %q", ErrInvalidValue, e.Reason, e.Pos, e.Input)
+}
+
+func (e *IXDTFError) Unwrap() error {
+	return ErrInvalidValue
+}
+
+// ParseIXDTF parses an RFC 9557 (IXDTF) string, rejecting offsets that are in
+// conflict with the zone.
+func ParseIXDTF(s string) (IXDTF, error) {
+	return ParseIXDTFWithOptions(s, IXDTFOptions{})
+}
+
+// ParseIXDTFWithOptions parses an RFC 9557 (IXDTF) string.
+//
+// The date time must have a UTC offset unless it is followed by a zone, then
+// the wall clock time is resolved in the zone. "Z" and "-00:00" mean that the
+// instant is known but not the local offset, so they are never in conflict
+// with a zone.
+// Unknown elective tags are kept in Tags, unknown critical tags and critical
+// non Gregorian calendars are rejected.
+func ParseIXDTFWithOptions(s string, opts IXDTFOptions) (IXDTF, error) {
+	fail := func(pos int, format string, args ...any) (IXDTF, error) {
+		return IXDTF{}, &IXDTFError{Input: s, Pos: pos, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	base, suffix := s, ""
+	if i := strings.IndexByte(s, '['); i >= 0 {
+		base, suffix = s[:i], s[i:]
+	}
+
+	var v IXDTF
+	var zoneLoc *time.Location
+
+	// Parse the suffixes, the zone must be the first one
+	pos := len(base)
+	for suffix != "" {
+		end := strings.IndexByte(suffix, ']')
+		if suffix[0] != '[' || end < 0 {
+			return fail(pos, "expected a bracketed suffix")
+		}
+		content, critical := suffix[1:end], false
+		if strings.HasPrefix(content, "!") {
+			content, critical = content[1:], true
+		}
+
+		if key, value, isTag := strings.Cut(content, "="); isTag {
+			if !ixdtfKeyRegexp.MatchString(key) {
+				return fail(pos, "invalid suffix tag key %q", key)
+			}
+			if !ixdtfValueRegexp.MatchString(value) {
+				return fail(pos, "invalid suffix tag value %q", value)
+			}
+			if key == IXDTFCalendarKey {
+				if v.Calendar == "" {
+					v.Calendar = value
+				} else if value != v.Calendar && (critical || v.calendarCritical()) {
+					return fail(pos, "conflicting critical calendars %q and %q", v.Calendar, value)
+				}
+			} else if critical {
+				return fail(pos, "unsupported critical suffix tag %q", key)
+			}
+			v.Tags = append(v.Tags, IXDTFTag{Key: key, Value: value, Critical: critical})
+		} else {
+			if pos != len(base) {
+				return fail(pos, "the time zone must be the first suffix")
+			}
+			loc, err := ixdtfZone(content)
+			if err != nil {
+				return fail(pos+1, "%v", err)
+			}
+			v.Zone, v.ZoneCritical, zoneLoc = content, critical, loc
+		}
+
+		pos += end + 1
+		suffix = suffix[end+1:]
+	}
+
+	if v.calendarCritical() && !ixdtfGregorianCalendars[v.Calendar] {
+		return fail(len(base), "unsupported critical calendar %q", v.Calendar)
+	}
+
+	// Parse the date time, the separators are case insensitive
+	normalized := []byte(base)
+	if len(normalized) > 10 && (normalized[10] == 't' || normalized[10] == ' ') {
+		normalized[10] = 'T'
+	}
+	if n := len(normalized); n > 0 && normalized[n-1] == 'z' {
+		normalized[n-1] = 'Z'
+	}
+
+	t, err := time.Parse(IXDTFLayout, string(normalized))
+	if err != nil {
+		if zoneLoc == nil {
+			return fail(0, "expected a date time with a UTC offset")
+		}
+		if t, err = time.ParseInLocation("2006-01-02T15:04:05.999999999", string(normalized), zoneLoc); err != nil {
+			return fail(0, "expected a date time")
+		}
+		v.Time = t
+		return v, nil
+	}
+
+	if zoneLoc == nil {
+		v.Time = t
+		return v, nil
+	}
+
+	// Resolve the offset against the zone
+	v.Time = t.In(zoneLoc)
+	_, offset := t.Zone()
+	_, zoneOffset := v.Time.Zone()
+	if strings.HasSuffix(string(normalized), "Z") || strings.HasSuffix(string(normalized), "-00:00") || offset == zoneOffset {
+		return v, nil
+	}
+
+	conflict := opts.OffsetConflict
+	if v.ZoneCritical {
+		conflict = IXDTFRejectConflict
+	}
+	switch conflict {
+	case IXDTFPreferOffset:
+		return v, nil
+	case IXDTFPreferZone:
+		v.Time = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), zoneLoc)
+		return v, nil
+	default:
+		return fail(len(base), "offset %s is not used by time zone %s at that time (%s)",
+			t.Format("Z07:00"), v.Zone, v.Time.Format("Z07:00"))
+	}
+}
+
+// ixdtfZone returns the location of a bracketed IANA zone ID or numeric
+// offset.
+func ixdtfZone(zone string) (*time.Location, error) {
+	if ixdtfNumericZoneRegexp.MatchString(zone) {
+		t, err := time.Parse("Z07:00", zone[:3]+":"+strings.TrimPrefix(zone[3:], ":"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid offset time zone %q", zone)
+		}
+		_, offset := t.Zone()
+		return time.FixedZone(zone, offset), nil
+	}
+	loc, err := LoadLocation(zone)
+	if err != nil || zone == "" || zone == "Local" {
+		return nil, fmt.Errorf("unknown time zone %q", zone)
+	}
+	return loc, nil
+}
+
+func (v IXDTF) calendarCritical() bool {
+	for _, tag := range v.Tags {
+		if tag.Key == IXDTFCalendarKey && tag.Critical {
+			return true
+		}
+	}
+	return false
+}
+
+// ZonedDateTime returns the instant of v in its zone. A numeric offset zone,
+// or no zone at all, results in a ZonedDateTime with just a UTC offset.
+func (v IXDTF) ZonedDateTime() ZonedDateTime {
+	if v.Zone == "" || ixdtfNumericZoneRegexp.MatchString(v.Zone) {
+		return ZonedDateTime{t: v.Time, loc: v.Time.Location()}
+	}
+	return ZonedDateTime{t: v.Time, loc: v.Time.Location(), zone: v.Zone}
+}
+
+// String returns v formatted by FormatIXDTF.
+func (v IXDTF) String() string {
+	return FormatIXDTF(v)
+}
+
+// FormatIXDTF formats v as an RFC 9557 (IXDTF) string, the date time in the
+// location of v.Time with its UTC offset followed by the zone and the tags.
+//
+// The Calendar is added as a "u-ca" tag unless Tags already has one.
+func FormatIXDTF(v IXDTF) string {
+	var b strings.Builder
+	b.WriteString(v.Time.Format(IXDTFLayout))
+
+	if v.Zone != "" {
+		b.WriteByte('[')
+		if v.ZoneCritical {
+			b.WriteByte('!')
+		}
+		b.WriteString(v.Zone)
+		b.WriteByte(']')
+	}
+
+	hasCalendar := false
+	for _, tag := range v.Tags {
+		hasCalendar = hasCalendar || tag.Key == IXDTFCalendarKey
+	}
+	if v.Calendar != "" && !hasCalendar {
+		fmt.Fprintf(&b, "[%s=%s]", IXDTFCalendarKey, v.Calendar)
+	}
+	for _, tag := range v.Tags {
+		b.WriteByte('[')
+		if tag.Critical {
+			b.WriteByte('!')
+		}
+		fmt.Fprintf(&b, "%s=%s]", tag.Key, tag.Value)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,134 @@
+package datetime
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseIXDTF(t *testing.T) {
+	_require := require.New(t)
+
+	v, err := ParseIXDTF("2024-02-14T09:00:00.5+01:00[!Europe/Stockholm][u-ca=gregory][_foo=bar-baz]")
+	_require.Nil(err)
+	_require.Equal("2024-02-14T08:00:00.5Z", v.Time.UTC().Format(IXDTFLayout))
+	_require.Equal("Europe/Stockholm", v.Time.Location().String())
+	_require.Equal("Europe/Stockholm", v.Zone)
+	_require.True(v.ZoneCritical)
+	_require.Equal("gregory", v.Calendar)
+	_require.Equal([]IXDTFTag{
+		{Key: "u-ca", Value: "gregory"},
+		{Key: "_foo", Value: "bar-baz"},
+	}, v.Tags)
+	_require.Equal("2024-02-14T09:00:00.5+01:00[!Europe/Stockholm][u-ca=gregory][_foo=bar-baz]", FormatIXDTF(v))
+
+	// No suffix at all is a plain RFC 3339 date time
+	v, err = ParseIXDTF("2024-02-14t09:00:00z")
+	_require.Nil(err)
+	_require.Equal("", v.Zone)
+	_require.Equal("2024-02-14T09:00:00Z", v.String())
+
+	// A numeric offset zone
+	v, err = ParseIXDTF("2024-02-14T09:00:00+01:00[+01:00]")
+	_require.Nil(err)
+	_require.Equal("+01:00", v.Zone)
+	_require.Equal("", v.ZonedDateTime().ZoneID())
+	_require.Equal("2024-02-14T09:00:00+01:00", v.ZonedDateTime().String())
+
+	// Without an offset the wall clock time is resolved in the zone
+	v, err = ParseIXDTF("2024-07-14T09:00:00[Europe/Stockholm]")
+	_require.Nil(err)
+	_require.Equal("2024-07-14T09:00:00+02:00[Europe/Stockholm]", v.String())
+
+	// "Z" and "-00:00" mean that the local offset is unknown, they are never
+	// in conflict
+	v, err = ParseIXDTF("2024-02-14T08:00:00Z[!Europe/Stockholm]")
+	_require.Nil(err)
+	_require.Equal("2024-02-14T09:00:00+01:00[!Europe/Stockholm]", v.String())
+	v, err = ParseIXDTF("2024-02-14T08:00:00-00:00[!Europe/Stockholm]")
+	_require.Nil(err)
+	_require.Equal("2024-02-14T09:00:00+01:00[!Europe/Stockholm]", v.String())
+
+	// Several calendars, the first elective one wins
+	v, err = ParseIXDTF("2024-02-14T09:00:00+01:00[u-ca=gregory][u-ca=japanese]")
+	_require.Nil(err)
+	_require.Equal("gregory", v.Calendar)
+
+	// An unsupported elective calendar is kept but ignored
+	v, err = ParseIXDTF("2024-02-14T09:00:00+01:00[u-ca=hebrew]")
+	_require.Nil(err)
+	_require.Equal("hebrew", v.Calendar)
+}
+
+func Test_ParseIXDTF_OffsetConflict(t *testing.T) {
+	_require := require.New(t)
+
+	from := "2024-07-14T09:00:00+01:00[Europe/Stockholm]"
+
+	_, err := ParseIXDTF(from)
+	_require.ErrorIs(err, ErrInvalidValue)
+
+	v, err := ParseIXDTFWithOptions(from, IXDTFOptions{OffsetConflict: IXDTFPreferOffset})
+	_require.Nil(err)
+	_require.Equal("2024-07-14T10:00:00+02:00[Europe/Stockholm]", v.String())
+
+	v, err = ParseIXDTFWithOptions(from, IXDTFOptions{OffsetConflict: IXDTFPreferZone})
+	_require.Nil(err)
+	_require.Equal("2024-07-14T09:00:00+02:00[Europe/Stockholm]", v.String())
+
+	// A critical zone always rejects a conflicting offset
+	_, err = ParseIXDTFWithOptions(
+		"2024-07-14T09:00:00+01:00[!Europe/Stockholm]",
+		IXDTFOptions{OffsetConflict: IXDTFPreferZone},
+	)
+	_require.ErrorIs(err, ErrInvalidValue)
+}
+
+func Test_ParseIXDTF_Errors(t *testing.T) {
+	for _, test := range []struct {
+		from string
+		pos  int
+	}{
+		{"2024-02-14T09:00:00", 0},                                      // no offset and no zone
+		{"2024-02-14X09:00:00+01:00", 0},                                // not a date time
+		{"2024-02-14T09:00:00+01:00[Fake/Ness]", 26},                    // unknown zone
+		{"2024-02-14T09:00:00+01:00[Europe/Stockholm", 25},              // unterminated
+		{"2024-02-14T09:00:00+01:00[u-ca=gregory][UTC]", 39},            // zone not first
+		{"2024-02-14T09:00:00+01:00[UTC][Europe/Stockholm]", 30},        // several zones
+		{"2024-02-14T09:00:00+01:00[!foo=bar]", 25},                     // unknown critical tag
+		{"2024-02-14T09:00:00+01:00[Foo=bar]", 25},                      // upper case key
+		{"2024-02-14T09:00:00+01:00[foo=b_r]", 25},                      // invalid value
+		{"2024-02-14T09:00:00+01:00[!u-ca=hebrew]", 25},                 // unsupported critical calendar
+		{"2024-02-14T09:00:00+01:00[u-ca=gregory][!u-ca=japanese]", 39}, // conflicting critical calendars
+	} {
+		t.Run(test.from, func(t *testing.T) {
+			_, err := ParseIXDTF(test.from)
+			require.ErrorIs(t, err, ErrInvalidValue)
+
+			var ixdtfErr *IXDTFError
+			require.True(t, errors.As(err, &ixdtfErr))
+			require.Equal(t, test.from, ixdtfErr.Input)
+			require.Equal(t, test.pos, ixdtfErr.Pos)
+		})
+	}
+}
+
+func Test_FormatIXDTF(t *testing.T) {
+	_require := require.New(t)
+
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	_require.Nil(err)
+
+	tm := time.Date(2024, 2, 14, 9, 0, 0, 0, stockholm)
+	_require.Equal("2024-02-14T09:00:00+01:00", FormatIXDTF(IXDTF{Time: tm}))
+	_require.Equal(
+		"2024-02-14T09:00:00+01:00[Europe/Stockholm][u-ca=iso8601]",
+		FormatIXDTF(IXDTF{Time: tm, Zone: "Europe/Stockholm", Calendar: "iso8601"}),
+	)
+
+	z, err := ParseIXDTF(FormatIXDTF(IXDTF{Time: tm, Zone: "Europe/Stockholm"}))
+	_require.Nil(err)
+	_require.True(tm.Equal(z.Time))
+}
@@ -0,0 +1,294 @@
+package datetime
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The locale data is embedded, like the time zone data of time/tzdata, so
+// that formatting works without any files on the host.
+//
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// Style is the length of a localized date/time, from "2024-02-14 09:00" to
+// "onsdag 14 februari 2024 kl. 09:00".
+type Style int
+
+const (
+	StyleShort Style = iota
+	StyleMedium
+	StyleLong
+	StyleFull
+)
+
+type localeStyles struct {
+	Short  string `json:"short"`
+	Medium string `json:"medium"`
+	Long   string `json:"long"`
+	Full   string `json:"full"`
+}
+
+func (s localeStyles) pattern(style Style) string {
+	switch style {
+	case StyleMedium:
+		return s.Medium
+	case StyleLong:
+		return s.Long
+	case StyleFull:
+		return s.Full
+	default:
+		return s.Short
+	}
+}
+
+// locale is the data of a locales/<tag>.json file.
+type locale struct {
+	Tag           string       `json:"tag"`
+	Months        []string     `json:"months"`
+	MonthsFormat  []string     `json:"monthsFormat"` // inflected month names used in dates, if different
+	MonthsShort   []string     `json:"monthsShort"`
+	Weekdays      []string     `json:"weekdays"` // starting with Sunday, like time.Weekday
+	WeekdaysShort []string     `json:"weekdaysShort"`
+	AM            string       `json:"am"`
+	PM            string       `json:"pm"`
+	Date          localeStyles `json:"date"`
+	Time          localeStyles `json:"time"`
+	DateTime      localeStyles `json:"dateTime"` // combines {date} and {time}
+	Relative      struct {
+		Today     string `json:"today"`
+		Tomorrow  string `json:"tomorrow"`
+		Yesterday string `json:"yesterday"`
+		DateTime  string `json:"dateTime"` // combines {day} and {time}
+	} `json:"relative"`
+	Humanize struct {
+		JustNow       string       `json:"justNow"`
+		Minutes       localeCounts `json:"minutes"`
+		Hours         localeCounts `json:"hours"`
+		WeekdayPast   string       `json:"weekdayPast"`   // "last {weekday}"
+		WeekdayFuture string       `json:"weekdayFuture"` // "on {weekday}"
+	} `json:"humanize"`
+}
+
+// localeCounts are the phrases of a counted unit, "{n} minutes ago", in the
+// past and in the future, for one and for other counts.
+type localeCounts struct {
+	PastOne     string `json:"pastOne"`
+	PastOther   string `json:"pastOther"`
+	FutureOne   string `json:"futureOne"`
+	FutureOther string `json:"futureOther"`
+}
+
+func (c localeCounts) pattern(n int, future bool) string {
+	switch {
+	case future && n == 1:
+		return c.FutureOne
+	case future:
+		return c.FutureOther
+	case n == 1:
+		return c.PastOne
+	default:
+		return c.PastOther
+	}
+}
+
+// Locale tags that are resolved to another locale.
+var localeAliases = map[string]string{
+	"no": "nb",
+	"nn": "nb",
+}
+
+var (
+	locales     map[string]*locale
+	localesErr  error
+	localesOnce sync.Once
+)
+
+func loadLocales() (map[string]*locale, error) {
+	localesOnce.Do(func() {
+		entries, err := localeFiles.ReadDir("locales")
+		if err != nil {
+			localesErr = err
+			return
+		}
+		locales = make(map[string]*locale, len(entries))
+		for _, entry := range entries {
+			data, err := localeFiles.ReadFile(path.Join("locales", entry.Name()))
+			if err != nil {
+				localesErr = err
+				return
+			}
+			l := &locale{}
+			if err := json.Unmarshal(data, l); err != nil {
+				localesErr = fmt.Errorf("locale %s: %w", entry.Name(), err)
+				return
+			}
+			if len(l.MonthsFormat) == 0 {
+				l.MonthsFormat = l.Months
+			}
+			locales[strings.ToLower(l.Tag)] = l
+		}
+	})
+	return locales, localesErr
+}
+
+// lookupLocale returns the locale of a BCP 47 tag such as "sv-SE", falling
+// back to the language, "sv", when there is no locale for the region.
+func lookupLocale(tag string) (*locale, error) {
+	all, err := loadLocales()
+	if err != nil {
+		return nil, err
+	}
+
+	tag = strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+	language, _, _ := strings.Cut(tag, "-")
+	for _, candidate := range []string{tag, language, localeAliases[language]} {
+		if l, ok := all[candidate]; ok {
+			return l, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unsupported locale %q", ErrInvalidValue, tag)
+}
+
+// Locales returns the tags of the supported locales.
+func Locales() []string {
+	all, _ := loadLocales()
+	tags := make([]string, 0, len(all))
+	for _, l := range all {
+		tags = append(tags, l.Tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// FormatLocalized formats the date and time of t in the location loc, or the
+// location of t if loc is nil, for a locale such as "sv-SE".
+func FormatLocalized(t time.Time, loc *time.Location, locale string, style Style) (string, error) {
+	l, err := lookupLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	if loc != nil {
+		t = t.In(loc)
+	}
+	return l.expand(l.DateTime.pattern(style), map[string]string{
+		"date": l.format(t, l.Date.pattern(style)),
+		"time": l.format(t, l.Time.pattern(style)),
+	}), nil
+}
+
+// FormatLocalizedDate formats the date d for a locale such as "sv-SE".
+func FormatLocalizedDate(d Date, locale string, style Style) (string, error) {
+	l, err := lookupLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	return l.format(d.In(time.UTC), l.Date.pattern(style)), nil
+}
+
+// FormatLocalizedTime formats the time of day of t in the location loc, or
+// the location of t if loc is nil, for a locale such as "sv-SE".
+func FormatLocalizedTime(t time.Time, loc *time.Location, locale string, style Style) (string, error) {
+	l, err := lookupLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	if loc != nil {
+		t = t.In(loc)
+	}
+	return l.format(t, l.Time.pattern(style)), nil
+}
+
+// FormatLocalizedRelative is like FormatLocalized, but the date is replaced by
+// "today", "tomorrow" or "yesterday" when t is on one of those days relative
+// to now, as seen in the location loc. Such as "i morgon kl. 09:00".
+func FormatLocalizedRelative(t, now time.Time, loc *time.Location, locale string, style Style) (string, error) {
+	l, err := lookupLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	if loc == nil {
+		loc = t.Location()
+	}
+	t, now = t.In(loc), now.In(loc)
+
+	var day string
+	today := DateOf(now)
+	switch DateOf(t) {
+	case today:
+		day = l.Relative.Today
+	case today.AddDays(1):
+		day = l.Relative.Tomorrow
+	case today.AddDays(-1):
+		day = l.Relative.Yesterday
+	default:
+		return FormatLocalized(t, loc, locale, style)
+	}
+	return l.expand(l.Relative.DateTime, map[string]string{
+		"day":  day,
+		"time": l.format(t, l.Time.pattern(style)),
+	}), nil
+}
+
+// format expands the date/time fields of a pattern such as "{d} {MMMM} {yyyy}".
+func (l *locale) format(t time.Time, pattern string) string {
+	hour12 := t.Hour() % 12
+	if hour12 == 0 {
+		hour12 = 12
+	}
+	ampm := l.AM
+	if t.Hour() >= 12 {
+		ampm = l.PM
+	}
+	return l.expand(pattern, map[string]string{
+		"d":    strconv.Itoa(t.Day()),
+		"dd":   fmt.Sprintf("%02d", t.Day()),
+		"M":    strconv.Itoa(int(t.Month())),
+		"MM":   fmt.Sprintf("%02d", int(t.Month())),
+		"MMM":  l.MonthsShort[t.Month()-1],
+		"MMMM": l.MonthsFormat[t.Month()-1],
+		"LLLL": l.Months[t.Month()-1],
+		"yy":   fmt.Sprintf("%02d", t.Year()%100),
+		"yyyy": fmt.Sprintf("%04d", t.Year()),
+		"EEE":  l.WeekdaysShort[t.Weekday()],
+		"EEEE": l.Weekdays[t.Weekday()],
+		"H":    strconv.Itoa(t.Hour()),
+		"HH":   fmt.Sprintf("%02d", t.Hour()),
+		"h":    strconv.Itoa(hour12),
+		"mm":   fmt.Sprintf("%02d", t.Minute()),
+		"ss":   fmt.Sprintf("%02d", t.Second()),
+		"a":    ampm,
+	})
+}
+
+// expand replaces the "{name}" fields of a pattern, unknown fields are kept.
+func (l *locale) expand(pattern string, fields map[string]string) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(pattern, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(pattern[start:], '}')
+		if end < 0 {
+			break
+		}
+		b.WriteString(pattern[:start])
+		name := pattern[start+1 : start+end]
+		if value, ok := fields[name]; ok {
+			b.WriteString(value)
+		} else {
+			b.WriteString(pattern[start : start+end+1])
+		}
+		pattern = pattern[start+end+1:]
+	}
+	b.WriteString(pattern)
+	return b.String()
+}
@@ -0,0 +1,122 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FormatLocalized(t *testing.T) {
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	require.Nil(t, err)
+
+	// 2024-02-14T09:05:00+01:00, a Wednesday
+	tm := time.Date(2024, 2, 14, 8, 5, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		locale                    string
+		short, medium, long, full string
+	}{
+		{
+			"sv-SE",
+			"2024-02-14 09:05",
+			"14 feb. 2024 09:05:00",
+			"14 februari 2024 kl. 09:05",
+			"onsdag 14 februari 2024 kl. 09:05",
+		},
+		{
+			"nb-NO",
+			"14.02.2024, 09:05",
+			"14. feb. 2024, 09:05:00",
+			"14. februar 2024 kl. 09:05",
+			"onsdag 14. februar 2024 kl. 09:05",
+		},
+		{
+			"da",
+			"14.02.2024 09.05",
+			"14. feb. 2024 09.05.00",
+			"14. februar 2024 kl. 09.05",
+			"onsdag den 14. februar 2024 kl. 09.05",
+		},
+		{
+			"fi-FI",
+			"14.2.2024 9.05",
+			"14.2.2024 klo 9.05.00",
+			"14. helmikuuta 2024 klo 9.05",
+			"keskiviikko 14. helmikuuta 2024 klo 9.05",
+		},
+		{
+			"en-GB",
+			"14/02/2024, 09:05",
+			"14 Feb 2024, 09:05:00",
+			"14 February 2024 at 09:05",
+			"Wednesday, 14 February 2024 at 09:05",
+		},
+		{
+			"en_US",
+			"2/14/24, 9:05 AM",
+			"Feb 14, 2024, 9:05:00 AM",
+			"February 14, 2024 at 9:05 AM",
+			"Wednesday, February 14, 2024 at 9:05 AM",
+		},
+	} {
+		t.Run(test.locale, func(t *testing.T) {
+			for style, expected := range []string{test.short, test.medium, test.long, test.full} {
+				formatted, err := FormatLocalized(tm, stockholm, test.locale, Style(style))
+				require.Nil(t, err)
+				require.Equal(t, expected, formatted)
+			}
+		})
+	}
+}
+
+func Test_FormatLocalized_Locales(t *testing.T) {
+	_require := require.New(t)
+
+	_require.Equal([]string{"da", "en", "en-US", "fi", "nb", "sv"}, Locales())
+
+	// "no" is resolved to Norwegian Bokmål
+	formatted, err := FormatLocalizedDate(NewDate(2024, 12, 24), "no", StyleLong)
+	_require.Nil(err)
+	_require.Equal("24. desember 2024", formatted)
+
+	// 12h clock at noon and midnight
+	formatted, err = FormatLocalizedTime(time.Date(2024, 2, 14, 12, 30, 0, 0, time.UTC), nil, "en-US", StyleShort)
+	_require.Nil(err)
+	_require.Equal("12:30 PM", formatted)
+	formatted, err = FormatLocalizedTime(time.Date(2024, 2, 14, 0, 30, 0, 0, time.UTC), nil, "en-US", StyleShort)
+	_require.Nil(err)
+	_require.Equal("12:30 AM", formatted)
+
+	_, err = FormatLocalized(time.Now(), nil, "de-DE", StyleShort)
+	_require.ErrorIs(err, ErrInvalidValue)
+}
+
+func Test_FormatLocalizedRelative(t *testing.T) {
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	require.Nil(t, err)
+
+	// Late in the evening in Stockholm, but already the 14th in UTC
+	now := time.Date(2024, 2, 13, 23, 30, 0, 0, stockholm)
+
+	for _, test := range []struct {
+		t        time.Time
+		locale   string
+		expected string
+	}{
+		{time.Date(2024, 2, 14, 9, 0, 0, 0, stockholm), "sv", "i morgon kl. 09:00"},
+		{time.Date(2024, 2, 14, 9, 0, 0, 0, stockholm), "en", "tomorrow at 09:00"},
+		{time.Date(2024, 2, 14, 9, 0, 0, 0, stockholm), "en-US", "tomorrow at 9:00 AM"},
+		{time.Date(2024, 2, 14, 9, 0, 0, 0, stockholm), "fi", "huomenna klo 9.00"},
+		{time.Date(2024, 2, 13, 9, 0, 0, 0, stockholm), "nb", "i dag kl. 09:00"},
+		{time.Date(2024, 2, 12, 9, 0, 0, 0, stockholm), "da", "i går kl. 09.00"},
+		{time.Date(2024, 2, 16, 9, 0, 0, 0, stockholm), "sv", "fredag 16 februari 2024 kl. 09:00"},
+	} {
+		t.Run(test.expected, func(t *testing.T) {
+			formatted, err := FormatLocalizedRelative(test.t.UTC(), now.UTC(), stockholm, test.locale, StyleFull)
+			require.Nil(t, err)
+			require.Equal(t, test.expected, formatted)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package datetime
+
+import (
+	"sync"
+	"time"
+)
+
+// locationCache holds the locations loaded by LoadLocation by name. Loading a
+// location from the embedded time zone data unzips and parses it, so it is
+// only done once per name. Failed loads are not cached, since the names can
+// come from user input.
+var locationCache sync.Map // map[string]*time.Location
+
+// LoadLocation is like time.LoadLocation, but returns a cached location when
+// the name has been loaded before. It is safe for concurrent use, and used by
+// all the converters of this package.
+//
+// Zones defined with DefineZone, and then the registered zone sources, are
+// used before the zone data of Go.
+func LoadLocation(name string) (*time.Location, error) {
+	switch name {
+	case "", "UTC":
+		return time.UTC, nil
+	case "Local":
+		return time.Local, nil
+	}
+
+	if l, ok := locationCache.Load(name); ok {
+		return l.(*time.Location), nil
+	}
+
+	// The cache is cleared when the sources change, so hold them until the
+	// location is stored
+	zoneSourcesMu.RLock()
+	defer zoneSourcesMu.RUnlock()
+
+	l, ok, err := loadFromZoneSources(name)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if l, err = time.LoadLocation(name); err != nil {
+			return nil, err
+		}
+	}
+	// Keep the first location stored if loaded concurrently
+	actual, _ := locationCache.LoadOrStore(name, l)
+	return actual.(*time.Location), nil
+}
+
+func clearLocationCache() {
+	locationCache.Range(func(name, _ any) bool {
+		locationCache.Delete(name)
+		return true
+	})
+}
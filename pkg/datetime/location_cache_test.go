@@ -0,0 +1,60 @@
+package datetime
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoadLocation(t *testing.T) {
+	_require := require.New(t)
+
+	stockholm, err := LoadLocation("Europe/Stockholm")
+	_require.Nil(err)
+	_require.Equal("Europe/Stockholm", stockholm.String())
+
+	// The same location is returned from the cache
+	again, err := LoadLocation("Europe/Stockholm")
+	_require.Nil(err)
+	_require.Same(stockholm, again)
+
+	utc, err := LoadLocation("")
+	_require.Nil(err)
+	_require.Same(time.UTC, utc)
+
+	_, err = LoadLocation("Europe/Stockholmm")
+	_require.NotNil(err)
+
+	// Concurrent loads agree on the location
+	var wg sync.WaitGroup
+	locations := make([]*time.Location, 10)
+	for i := range locations {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			locations[i], _ = LoadLocation("America/New_York")
+		}(i)
+	}
+	wg.Wait()
+	for _, l := range locations {
+		_require.Same(locations[0], l)
+	}
+}
+
+func BenchmarkLoadLocation(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := LoadLocation("Europe/Stockholm"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLoadLocation_Uncached(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := time.LoadLocation("Europe/Stockholm"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
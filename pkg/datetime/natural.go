@@ -0,0 +1,396 @@
+package datetime
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NaturalKind is the kind of a NaturalResult.
+type NaturalKind int
+
+const (
+	// NaturalInstant is a point in time, "tomorrow 14:30"
+	NaturalInstant NaturalKind = iota + 1
+	// NaturalDate is a whole day, "next tue"
+	NaturalDate
+	// NaturalRange is a span of time, "next week"
+	NaturalRange
+)
+
+// NaturalResult is the result of ParseNatural.
+type NaturalResult struct {
+	Kind NaturalKind
+	// Instant is set for NaturalInstant
+	Instant time.Time
+	// Date is set for NaturalDate
+	Date Date
+	// Start and End are the half-open [Start, End) span of a NaturalRange
+	Start, End time.Time
+	// Confidence is between 0 and 1, lowered by every ambiguity
+	Confidence float64
+	// Ambiguities describe the other ways the input could be understood
+	Ambiguities []string
+	// Rule is the name of the rule that matched
+	Rule string
+}
+
+// NaturalContext is what a NaturalRule resolves its match against.
+type NaturalContext struct {
+	// Now is the current time of the TimeProvider, in Location
+	Now      time.Time
+	Location *time.Location
+	Locale   string
+}
+
+// Today returns the current date in the location of the context.
+func (c NaturalContext) Today() Date {
+	return DateOf(c.Now)
+}
+
+// NaturalRule is a rule of the ParseNatural grammar. The pattern is matched
+// against the input in lower case with single spaces, and the submatches are
+// passed to Resolve.
+type NaturalRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+	Resolve func(ctx NaturalContext, match []string) (NaturalResult, error)
+}
+
+var (
+	naturalRulesMutex sync.RWMutex
+	naturalRules      = map[string][]NaturalRule{
+		"en": naturalVocabularyRules(englishVocabulary),
+		"sv": naturalVocabularyRules(swedishVocabulary),
+	}
+)
+
+// RegisterNaturalRules adds rules to the grammar of a language, such as "sv",
+// which makes it possible to support new expressions and languages. Rules
+// registered later are tried before earlier ones and before the built-in
+// rules.
+func RegisterNaturalRules(language string, rules ...NaturalRule) {
+	naturalRulesMutex.Lock()
+	defer naturalRulesMutex.Unlock()
+
+	language = strings.ToLower(language)
+	naturalRules[language] = append(append([]NaturalRule{}, rules...), naturalRules[language]...)
+}
+
+// ParseNatural parses a natural-language expression typed by a user, such as
+// "tomorrow 14:30", "next tue 9", "om 2 veckor" or "15/3", relative to the
+// current time of the TimeProvider in the location loc, UTC if nil. English
+// and Swedish are supported, see RegisterNaturalRules for adding more.
+func ParseNatural(input string, tp TimeProvider, loc *time.Location, locale string) (NaturalResult, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	language, _, _ := strings.Cut(strings.ToLower(strings.ReplaceAll(locale, "_", "-")), "-")
+
+	naturalRulesMutex.RLock()
+	rules := naturalRules[language]
+	naturalRulesMutex.RUnlock()
+	if rules == nil {
+		return NaturalResult{}, fmt.Errorf("%w: unsupported locale %q", ErrInvalidValue, locale)
+	}
+
+	normalized := strings.Join(strings.Fields(strings.ToLower(input)), " ")
+	ctx := NaturalContext{Now: tp.Now().In(loc), Location: loc, Locale: locale}
+
+	for _, rule := range rules {
+		match := rule.Pattern.FindStringSubmatch(normalized)
+		if match == nil {
+			continue
+		}
+		result, err := rule.Resolve(ctx, match)
+		if err != nil {
+			return NaturalResult{}, err
+		}
+		result.Rule = rule.Name
+		result.Confidence = 1
+		for range result.Ambiguities {
+			result.Confidence *= 0.7
+		}
+		return result, nil
+	}
+	return NaturalResult{}, fmt.Errorf("%w: %q is not understood", ErrInvalidValue, input)
+}
+
+// naturalVocabulary are the words of a language used by the built-in rules.
+type naturalVocabulary struct {
+	days       map[string]int // days from today, "tomorrow": 1
+	weekdays   map[string]time.Weekday
+	next       []string // "next tue" is the coming Tuesday, after today
+	on         []string // "on tue" is also the coming Tuesday, but never ambiguous
+	this       []string // "this tue" is the Tuesday of the current week
+	in         string   // "in 2 weeks"
+	numbers    map[string]int
+	units      map[string]string // to "minute", "hour", "day" or "week"
+	week       []string
+	timePrefix []string // "at 9"
+	hour12     bool     // whether a bare hour like "9" may be both 9:00 and 21:00
+}
+
+var englishVocabulary = naturalVocabulary{
+	days: map[string]int{"today": 0, "tomorrow": 1, "tmrw": 1, "yesterday": -1},
+	weekdays: map[string]time.Weekday{
+		"sun": time.Sunday, "sunday": time.Sunday,
+		"mon": time.Monday, "monday": time.Monday,
+		"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+		"wed": time.Wednesday, "wednesday": time.Wednesday,
+		"thu": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+		"fri": time.Friday, "friday": time.Friday,
+		"sat": time.Saturday, "saturday": time.Saturday,
+	},
+	next:    []string{"next"},
+	on:      []string{"on"},
+	this:    []string{"this"},
+	in:      "in",
+	numbers: map[string]int{"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4},
+	units: map[string]string{
+		"min": "minute", "mins": "minute", "minute": "minute", "minutes": "minute",
+		"hour": "hour", "hours": "hour", "h": "hour",
+		"day": "day", "days": "day",
+		"week": "week", "weeks": "week",
+	},
+	week:       []string{"week"},
+	timePrefix: []string{"at"},
+	hour12:     true,
+}
+
+var swedishVocabulary = naturalVocabulary{
+	days: map[string]int{
+		"idag": 0, "i dag": 0, "imorgon": 1, "i morgon": 1,
+		"igår": -1, "i går": -1, "i övermorgon": 2, "övermorgon": 2,
+	},
+	weekdays: map[string]time.Weekday{
+		"sön": time.Sunday, "söndag": time.Sunday,
+		"mån": time.Monday, "måndag": time.Monday,
+		"tis": time.Tuesday, "tisdag": time.Tuesday,
+		"ons": time.Wednesday, "onsdag": time.Wednesday,
+		"tor": time.Thursday, "tors": time.Thursday, "torsdag": time.Thursday,
+		"fre": time.Friday, "fredag": time.Friday,
+		"lör": time.Saturday, "lördag": time.Saturday,
+	},
+	next:    []string{"nästa"},
+	on:      []string{"på"},
+	this:    []string{"denna", "den här"},
+	in:      "om",
+	numbers: map[string]int{"en": 1, "ett": 1, "två": 2, "tre": 3, "fyra": 4},
+	units: map[string]string{
+		"min": "minute", "minut": "minute", "minuter": "minute",
+		"timme": "hour", "timmar": "hour", "tim": "hour",
+		"dag": "day", "dagar": "day",
+		"vecka": "week", "veckor": "week",
+	},
+	week:       []string{"vecka", "veckan"},
+	timePrefix: []string{"kl", "kl.", "klockan"},
+}
+
+// naturalAlternation returns a regexp alternation of the words, longest first.
+func naturalAlternation(words []string) string {
+	sorted := append([]string{}, words...)
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	for i, word := range sorted {
+		sorted[i] = regexp.QuoteMeta(word)
+	}
+	return "(?:" + strings.Join(sorted, "|") + ")"
+}
+
+func naturalKeys[V any](words map[string]V) []string {
+	keys := make([]string, 0, len(words))
+	for key := range words {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// naturalVocabularyRules returns the built-in rules of a language.
+func naturalVocabularyRules(v naturalVocabulary) []NaturalRule {
+	timeCore := `(?:` + naturalAlternation(v.timePrefix) + `\s*)?(\d{1,2})(?:[:.](\d{2}))?\s*(am|pm)?`
+	timeOfDay := `(?:\s+` + timeCore + `)?`
+	days := "(" + naturalAlternation(naturalKeys(v.days)) + ")"
+	weekdays := "(" + naturalAlternation(naturalKeys(v.weekdays)) + ")"
+	next := "(" + naturalAlternation(append(append(append([]string{}, v.next...), v.on...), v.this...)) + ")"
+	count := `(\d+|` + naturalAlternation(naturalKeys(v.numbers)) + ")"
+	units := "(" + naturalAlternation(naturalKeys(v.units)) + ")"
+	week := naturalAlternation(v.week)
+
+	isThis := func(word string) bool { return slices.Contains(v.this, word) }
+
+	return []NaturalRule{
+		{
+			Name:    "relative day",
+			Pattern: regexp.MustCompile("^" + days + timeOfDay + "$"),
+			Resolve: func(ctx NaturalContext, match []string) (NaturalResult, error) {
+				date := ctx.Today().AddDays(v.days[match[1]])
+				return naturalDateTime(ctx, v, date, match[2:5], nil)
+			},
+		},
+		{
+			Name:    "weekday",
+			Pattern: regexp.MustCompile("^(?:" + next + `\s+)?` + weekdays + timeOfDay + "$"),
+			Resolve: func(ctx NaturalContext, match []string) (NaturalResult, error) {
+				today := ctx.Today()
+				weekday := v.weekdays[match[2]]
+				monday := startOfWeek(today)
+
+				var ambiguities []string
+				var date Date
+				if isThis(match[1]) {
+					date = monday.AddDays((int(weekday) + 6) % 7)
+				} else {
+					days := (int(weekday) - int(today.Weekday()) + 7) % 7
+					if days == 0 {
+						days = 7
+					}
+					date = today.AddDays(days)
+					if slices.Contains(v.next, match[1]) && date.Before(monday.AddDays(7)) {
+						ambiguities = append(ambiguities, fmt.Sprintf("may mean %s, in the week after", date.AddDays(7)))
+					}
+				}
+				return naturalDateTime(ctx, v, date, match[3:6], ambiguities)
+			},
+		},
+		{
+			Name:    "day/month",
+			Pattern: regexp.MustCompile(`^(\d{1,2})/(\d{1,2})(?:/(\d{2}|\d{4}))?` + timeOfDay + "$"),
+			Resolve: func(ctx NaturalContext, match []string) (NaturalResult, error) {
+				first, _ := strconv.Atoi(match[1])
+				second, _ := strconv.Atoi(match[2])
+				day, month := first, second
+				if monthFirstLocale(ctx.Locale) {
+					day, month = second, first
+				}
+
+				var ambiguities []string
+				if first != second && first <= 12 && second <= 12 {
+					ambiguities = append(ambiguities, fmt.Sprintf("may mean day %d of month %d", month, day))
+				}
+
+				today := ctx.Today()
+				year := today.Year
+				if match[3] != "" {
+					year, _ = strconv.Atoi(match[3])
+					if year < 100 {
+						year += 2000
+					}
+				}
+				date := NewDate(year, time.Month(month), day)
+				if match[3] == "" {
+					// The next such date, from today, such as the next
+					// leap year for "29/2"
+					if date.IsValid() && date.Before(today) {
+						ambiguities = append(ambiguities, fmt.Sprintf("may mean %s, in the past", date))
+					}
+					for i := 0; i < 8 && (!date.IsValid() || date.Before(today)); i++ {
+						date.Year++
+					}
+				}
+				if !date.IsValid() {
+					return NaturalResult{}, fmt.Errorf("%w: %q is not a date", ErrInvalidValue, match[0])
+				}
+				return naturalDateTime(ctx, v, date, match[4:7], ambiguities)
+			},
+		},
+		{
+			Name:    "in duration",
+			Pattern: regexp.MustCompile("^" + regexp.QuoteMeta(v.in) + `\s+` + count + `\s+` + units + "$"),
+			Resolve: func(ctx NaturalContext, match []string) (NaturalResult, error) {
+				n, ok := v.numbers[match[1]]
+				if !ok {
+					var err error
+					if n, err = strconv.Atoi(match[1]); err != nil {
+						return NaturalResult{}, fmt.Errorf("%w: %q is not a count: %v", ErrInvalidValue, match[1], err)
+					}
+				}
+				switch v.units[match[2]] {
+				case "minute":
+					return NaturalResult{Kind: NaturalInstant, Instant: ctx.Now.Add(time.Duration(n) * time.Minute)}, nil
+				case "hour":
+					return NaturalResult{Kind: NaturalInstant, Instant: ctx.Now.Add(time.Duration(n) * time.Hour)}, nil
+				case "week":
+					n *= 7
+				}
+				return NaturalResult{Kind: NaturalDate, Date: ctx.Today().AddDays(n)}, nil
+			},
+		},
+		{
+			Name:    "week",
+			Pattern: regexp.MustCompile("^" + next + `\s+` + week + "$"),
+			Resolve: func(ctx NaturalContext, match []string) (NaturalResult, error) {
+				monday := startOfWeek(ctx.Today())
+				if !isThis(match[1]) {
+					monday = monday.AddDays(7)
+				}
+				return NaturalResult{
+					Kind:  NaturalRange,
+					Start: monday.In(ctx.Location),
+					End:   monday.AddDays(7).In(ctx.Location),
+				}, nil
+			},
+		},
+		{
+			Name:    "time of day",
+			Pattern: regexp.MustCompile("^" + timeCore + "$"),
+			Resolve: func(ctx NaturalContext, match []string) (NaturalResult, error) {
+				return naturalDateTime(ctx, v, ctx.Today(), match[1:4], nil)
+			},
+		},
+	}
+}
+
+// naturalDateTime returns the date, or the instant on the date when the
+// hour, minute and am/pm submatches have an hour.
+func naturalDateTime(
+	ctx NaturalContext,
+	v naturalVocabulary,
+	date Date,
+	timeMatch []string,
+	ambiguities []string,
+) (NaturalResult, error) {
+	if timeMatch[0] == "" {
+		return NaturalResult{Kind: NaturalDate, Date: date, Ambiguities: ambiguities}, nil
+	}
+
+	hour, _ := strconv.Atoi(timeMatch[0])
+	minute, _ := strconv.Atoi(timeMatch[1])
+	if timeMatch[2] != "" && (hour < 1 || hour > 12) {
+		return NaturalResult{}, fmt.Errorf("%w: %d%s is not a time of day", ErrInvalidValue, hour, timeMatch[2])
+	}
+	switch timeMatch[2] {
+	case "am":
+		hour %= 12
+	case "pm":
+		hour = hour%12 + 12
+	default:
+		if v.hour12 && hour >= 1 && hour <= 11 {
+			ambiguities = append(ambiguities, fmt.Sprintf("may mean %02d:%02d", hour+12, minute))
+		}
+	}
+
+	timeOfDay := NewTimeOfDay(hour, minute, 0)
+	if !timeOfDay.IsValid() {
+		return NaturalResult{}, fmt.Errorf("%w: %s:%s is not a time of day", ErrInvalidValue, timeMatch[0], timeMatch[1])
+	}
+	return NaturalResult{
+		Kind:        NaturalInstant,
+		Instant:     timeOfDay.On(date, ctx.Location),
+		Ambiguities: ambiguities,
+	}, nil
+}
+
+// startOfWeek returns the Monday of the week of d.
+func startOfWeek(d Date) Date {
+	return d.AddDays(-((int(d.Weekday()) + 6) % 7))
+}
+
+// monthFirstLocale reports whether "3/4" is the 4th of March in the locale.
+func monthFirstLocale(locale string) bool {
+	return strings.EqualFold(strings.ReplaceAll(locale, "_", "-"), "en-US")
+}
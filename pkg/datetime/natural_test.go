@@ -0,0 +1,150 @@
+package datetime
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseNatural(t *testing.T) {
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	require.Nil(t, err)
+
+	// Wednesday 2024-02-14 10:00 in Stockholm, 09:00 in UTC
+	tp := NewFakeTimeProvider(time.Date(2024, 2, 14, 9, 0, 0, 0, time.UTC))
+
+	for _, test := range []struct {
+		input, locale string
+		kind          NaturalKind
+		expected      string // ISO8601 instant, date or "start/end"
+		ambiguities   int
+	}{
+		{"tomorrow 14:30", "en", NaturalInstant, "2024-02-15T14:30:00+01:00", 0},
+		{"Tomorrow at 2:30pm", "en-GB", NaturalInstant, "2024-02-15T14:30:00+01:00", 0},
+		{"today", "en", NaturalDate, "2024-02-14", 0},
+		{"yesterday 9am", "en", NaturalInstant, "2024-02-13T09:00:00+01:00", 0},
+		{"next tue 9", "en", NaturalInstant, "2024-02-20T09:00:00+01:00", 1},
+		{"next fri", "en", NaturalDate, "2024-02-16", 1},
+		{"on fri", "en", NaturalDate, "2024-02-16", 0},
+		{"this monday", "en", NaturalDate, "2024-02-12", 0},
+		{"wednesday", "en", NaturalDate, "2024-02-21", 0},
+		{"in 2 weeks", "en", NaturalDate, "2024-02-28", 0},
+		{"in an hour", "en", NaturalInstant, "2024-02-14T11:00:00+01:00", 0},
+		{"in 30 min", "en", NaturalInstant, "2024-02-14T10:30:00+01:00", 0},
+		{"next week", "en", NaturalRange, "2024-02-19T00:00:00+01:00/2024-02-26T00:00:00+01:00", 0},
+		{"15/3", "en", NaturalDate, "2024-03-15", 0},
+		{"3/15", "en-US", NaturalDate, "2024-03-15", 0},
+		{"3/4", "en", NaturalDate, "2024-04-03", 1},
+		{"1/2", "en", NaturalDate, "2025-02-01", 2},
+		{"15/3/2025 14:00", "en", NaturalInstant, "2025-03-15T14:00:00+01:00", 0},
+		{"14:30", "en", NaturalInstant, "2024-02-14T14:30:00+01:00", 0},
+		{"at 9", "en", NaturalInstant, "2024-02-14T09:00:00+01:00", 1},
+
+		{"i morgon 14:30", "sv", NaturalInstant, "2024-02-15T14:30:00+01:00", 0},
+		{"imorgon kl 9", "sv-SE", NaturalInstant, "2024-02-15T09:00:00+01:00", 0},
+		{"idag kl. 16.15", "sv", NaturalInstant, "2024-02-14T16:15:00+01:00", 0},
+		{"i övermorgon", "sv", NaturalDate, "2024-02-16", 0},
+		{"nästa tis 9", "sv", NaturalInstant, "2024-02-20T09:00:00+01:00", 0},
+		{"nästa fredag", "sv", NaturalDate, "2024-02-16", 1},
+		{"på fredag", "sv", NaturalDate, "2024-02-16", 0},
+		{"om 2 veckor", "sv", NaturalDate, "2024-02-28", 0},
+		{"om en timme", "sv", NaturalInstant, "2024-02-14T11:00:00+01:00", 0},
+		{"nästa vecka", "sv", NaturalRange, "2024-02-19T00:00:00+01:00/2024-02-26T00:00:00+01:00", 0},
+		{"den här veckan", "sv", NaturalRange, "2024-02-12T00:00:00+01:00/2024-02-19T00:00:00+01:00", 0},
+		{"15/3", "sv", NaturalDate, "2024-03-15", 0},
+		{"kl 8", "sv", NaturalInstant, "2024-02-14T08:00:00+01:00", 0},
+	} {
+		t.Run(test.locale+" "+test.input, func(t *testing.T) {
+			result, err := ParseNatural(test.input, tp, stockholm, test.locale)
+			require.Nil(t, err)
+			require.Equal(t, test.kind, result.Kind)
+
+			switch result.Kind {
+			case NaturalInstant:
+				require.Equal(t, test.expected, TimeToLocalISO8601DateTimeString(result.Instant, stockholm))
+			case NaturalDate:
+				require.Equal(t, test.expected, result.Date.String())
+			case NaturalRange:
+				require.Equal(t, test.expected, TimeToISO8601DateTimeString(result.Start)+"/"+TimeToISO8601DateTimeString(result.End))
+			}
+
+			require.Len(t, result.Ambiguities, test.ambiguities, result.Ambiguities)
+			if test.ambiguities == 0 {
+				require.Equal(t, 1.0, result.Confidence)
+			} else {
+				require.Less(t, result.Confidence, 1.0)
+			}
+		})
+	}
+}
+
+func Test_ParseNatural_Errors(t *testing.T) {
+	tp := NewFakeTimeProvider(time.Date(2024, 2, 14, 9, 0, 0, 0, time.UTC))
+
+	for _, input := range []string{"someday", "31/2", "tomorrow 25:00", "13pm", "next moon"} {
+		_, err := ParseNatural(input, tp, time.UTC, "en")
+		require.ErrorIsf(t, err, ErrInvalidValue, input)
+	}
+
+	_, err := ParseNatural("tomorrow", tp, time.UTC, "de")
+	require.ErrorIs(t, err, ErrInvalidValue)
+
+	_, err = ParseNatural("in 99999999999999999999 days", tp, time.UTC, "en")
+	require.ErrorIs(t, err, ErrInvalidValue)
+
+	// Without a location in UTC
+	result, err := ParseNatural("tomorrow 14:30", tp, nil, "en")
+	require.Nil(t, err)
+	require.Equal(t, time.Date(2024, 2, 15, 14, 30, 0, 0, time.UTC), result.Instant)
+}
+
+func Test_ParseNatural_LeapDay(t *testing.T) {
+	_require := require.New(t)
+
+	// 29/2 has passed for 2024 and does not exist until 2028
+	tp := NewFakeTimeProvider(time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC))
+
+	result, err := ParseNatural("29/2", tp, time.UTC, "sv")
+	_require.Nil(err)
+	_require.Equal(NaturalDate, result.Kind)
+	_require.Equal(NewDate(2028, 2, 29), result.Date)
+
+	result, err = ParseNatural("29/2 10:00", tp, time.UTC, "sv")
+	_require.Nil(err)
+	_require.Equal(NaturalInstant, result.Kind)
+	_require.Equal(time.Date(2028, 2, 29, 10, 0, 0, 0, time.UTC), result.Instant)
+}
+
+func Test_RegisterNaturalRules(t *testing.T) {
+	tp := NewFakeTimeProvider(time.Date(2024, 2, 14, 9, 0, 0, 0, time.UTC))
+
+	naturalRulesMutex.Lock()
+	rules, ok := naturalRules["da"]
+	naturalRulesMutex.Unlock()
+	t.Cleanup(func() {
+		naturalRulesMutex.Lock()
+		defer naturalRulesMutex.Unlock()
+		if ok {
+			naturalRules["da"] = rules
+		} else {
+			delete(naturalRules, "da")
+		}
+	})
+
+	RegisterNaturalRules("da", NaturalRule{
+		Name:    "om dage",
+		Pattern: regexp.MustCompile(`^om (\d+) dage$`),
+		Resolve: func(ctx NaturalContext, match []string) (NaturalResult, error) {
+			days, _ := strconv.Atoi(match[1])
+			return NaturalResult{Kind: NaturalDate, Date: ctx.Today().AddDays(days)}, nil
+		},
+	})
+
+	result, err := ParseNatural("om 3 dage", tp, time.UTC, "da-DK")
+	require.Nil(t, err)
+	require.Equal(t, "om dage", result.Rule)
+	require.Equal(t, NewDate(2024, 2, 17), result.Date)
+}
@@ -0,0 +1,320 @@
+package datetime
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/relvacode/iso8601"
+)
+
+// InputFormat is a date/time format detected by ParseAny.
+type InputFormat int
+
+const (
+	// FormatISO8601 is an extended ISO8601 date or date time, "2006-01-02T15:04:05Z07:00"
+	FormatISO8601 InputFormat = iota + 1
+	// FormatISO8601Basic is a compact ISO8601 date or date time, "20060102T150405Z0700"
+	FormatISO8601Basic
+	// FormatRFC1123 is an HTTP date, "Mon, 02 Jan 2006 15:04:05 MST"
+	FormatRFC1123
+	// FormatRFC850 is an obsolete HTTP date, "Monday, 02-Jan-06 15:04:05 MST"
+	FormatRFC850
+	// FormatSQLDateTime is a date time without an offset, "2006-01-02 15:04:05"
+	FormatSQLDateTime
+	// FormatUnixSeconds is the number of seconds since the Unix epoch
+	FormatUnixSeconds
+	// FormatUnixMillis is the number of milliseconds since the Unix epoch
+	FormatUnixMillis
+	// FormatExcelSerial is the number of days since 1899-12-30, with the time of
+	// day as the fraction. Since any short number is one, such as "2024", it is
+	// only tried when in ParseOptions.Formats.
+	FormatExcelSerial
+	// FormatDayMonthYear is a date with the day first, "02/01/2006" or "02.01.2006"
+	FormatDayMonthYear
+	// FormatMonthDayYear is a date with the month first, "01/02/2006"
+	FormatMonthDayYear
+)
+
+var inputFormatNames = map[InputFormat]string{
+	FormatISO8601:      "ISO8601",
+	FormatISO8601Basic: "ISO8601 basic",
+	FormatRFC1123:      "RFC 1123",
+	FormatRFC850:       "RFC 850",
+	FormatSQLDateTime:  "SQL date time",
+	FormatUnixSeconds:  "Unix seconds",
+	FormatUnixMillis:   "Unix milliseconds",
+	FormatExcelSerial:  "Excel serial date",
+	FormatDayMonthYear: "day/month/year",
+	FormatMonthDayYear: "month/day/year",
+}
+
+func (f InputFormat) String() string {
+	if name, ok := inputFormatNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("InputFormat(%d)", int(f))
+}
+
+// The formats tried by ParseAny, in order, when ParseOptions.Formats is empty.
+var defaultInputFormats = []InputFormat{
+	FormatISO8601,
+	FormatISO8601Basic,
+	FormatRFC1123,
+	FormatRFC850,
+	FormatSQLDateTime,
+	FormatUnixSeconds,
+	FormatUnixMillis,
+	FormatDayMonthYear,
+	FormatMonthDayYear,
+}
+
+var (
+	iso8601ExtendedRegexp = regexp.MustCompile(`^\d{4}-\d{2}(-\d{2}(T\d{2}(:\d{2}(:\d{2}([.,]\d+)?)?)?)?)?` + `(Z|[+-]\d{2}(:?\d{2})?)?$`)
+	iso8601OffsetRegexp   = regexp.MustCompile(`T.*(Z|[+-]\d{2}(:?\d{2})?)$`)
+	numericRegexp         = regexp.MustCompile(`^(\d+)(\.\d+)?$`)
+	slashDateRegexp       = regexp.MustCompile(`^(\d{1,2})[/.-](\d{1,2})[/.-](\d{4})$`)
+)
+
+var iso8601BasicLayouts = []string{
+	"20060102T150405.999999999Z0700",
+	"20060102T150405.999999999",
+	"20060102T1504Z0700",
+	"20060102T1504",
+	"20060102",
+}
+
+var rfc1123Layouts = []string{
+	time.RFC1123,
+	time.RFC1123Z,
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+}
+
+// The zone names allowed by RFC 5322 section 4.3, in hours east of UTC. Other
+// abbreviations are ambiguous, time.Parse would give them a zero offset.
+var rfc5322Zones = map[string]int{
+	"GMT": 0, "UT": 0, "UTC": 0, "Z": 0,
+	"EST": -5, "EDT": -4,
+	"CST": -6, "CDT": -5,
+	"MST": -7, "MDT": -6,
+	"PST": -8, "PDT": -7,
+}
+
+var sqlDateTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04",
+}
+
+// Excel serial dates count days from 1899-12-30, which absorbs Excel's
+// non-existent 1900-02-29 for all dates from March 1900.
+var excelEpoch = Date{Year: 1899, Month: time.December, Day: 30}
+
+// ParseOptions controls ParseAny.
+type ParseOptions struct {
+	// DefaultLocation is used for input without a UTC offset, UTC if nil
+	DefaultLocation *time.Location
+	// Formats are the allowed formats, all of them but FormatExcelSerial if
+	// empty
+	Formats []InputFormat
+	// RejectAmbiguous fails on day/month dates that also are valid
+	// month/day dates, such as "01/02/2024", instead of preferring the
+	// first allowed of FormatDayMonthYear and FormatMonthDayYear
+	RejectAmbiguous bool
+	// Strict only accepts the canonical ISO8601DateTime format, as produced
+	// by TimeToISO8601DateTimeString
+	Strict bool
+}
+
+// ParseResult is a Time parsed by ParseAny, with the format it was parsed from.
+type ParseResult struct {
+	Time   time.Time
+	Format InputFormat
+	// Layout is the time.Parse layout that matched, "" for numeric formats
+	Layout string
+}
+
+// ParseAny parses s in any of the allowed formats and reports which format
+// matched. Use it for input from integrations that don't send ISO8601.
+func ParseAny(s string, opts ParseOptions) (ParseResult, error) {
+	s = strings.TrimSpace(s)
+
+	loc := opts.DefaultLocation
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	if opts.Strict {
+		t, err := time.Parse(ISO8601DateTime, s)
+		if err != nil || TimeToISO8601DateTimeString(t) != s {
+			return ParseResult{}, fmt.Errorf("%w: %q is not a canonical ISO8601 date time", ErrInvalidValue, s)
+		}
+		return ParseResult{Time: t, Format: FormatISO8601, Layout: ISO8601DateTime}, nil
+	}
+
+	formats := opts.Formats
+	if len(formats) == 0 {
+		formats = defaultInputFormats
+	}
+
+	var slashMatches []ParseResult
+	for _, format := range formats {
+		result, ok := parseInputFormat(s, format, loc)
+		if !ok {
+			continue
+		}
+		if format != FormatDayMonthYear && format != FormatMonthDayYear {
+			return result, nil
+		}
+		slashMatches = append(slashMatches, result)
+	}
+
+	switch {
+	case len(slashMatches) == 0:
+		return ParseResult{}, fmt.Errorf("%w: %q is not in any of the formats %v", ErrInvalidValue, s, formats)
+	case len(slashMatches) > 1 && opts.RejectAmbiguous && !slashMatches[0].Time.Equal(slashMatches[1].Time):
+		return ParseResult{}, fmt.Errorf(
+			"%w: %q is ambiguous, it can be both %s and %s",
+			ErrInvalidValue, s, TimeToISO8601DateString(slashMatches[0].Time), TimeToISO8601DateString(slashMatches[1].Time),
+		)
+	default:
+		return slashMatches[0], nil
+	}
+}
+
+func parseInputFormat(s string, format InputFormat, loc *time.Location) (ParseResult, bool) {
+	result := ParseResult{Format: format}
+
+	switch format {
+	case FormatISO8601:
+		if !iso8601ExtendedRegexp.MatchString(s) {
+			return result, false
+		}
+		t, err := iso8601.ParseString(s)
+		if err != nil {
+			return result, false
+		}
+		if !iso8601OffsetRegexp.MatchString(s) {
+			t = inLocation(t, loc)
+		}
+		result.Time, result.Layout = t, ISO8601DateTime
+		switch len(s) {
+		case len(ISO8601Date):
+			result.Layout = ISO8601Date
+		case len("2006-01"):
+			result.Layout = "2006-01"
+		}
+		return result, true
+
+	case FormatISO8601Basic:
+		return parseLayouts(s, iso8601BasicLayouts, loc, result)
+
+	case FormatRFC1123:
+		return parseRFCLayouts(s, rfc1123Layouts, loc, result)
+
+	case FormatRFC850:
+		return parseRFCLayouts(s, []string{time.RFC850}, loc, result)
+
+	case FormatSQLDateTime:
+		return parseLayouts(s, sqlDateTimeLayouts, loc, result)
+
+	case FormatUnixSeconds, FormatUnixMillis, FormatExcelSerial:
+		match := numericRegexp.FindStringSubmatch(s)
+		if match == nil {
+			return result, false
+		}
+		value, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return result, false
+		}
+		digits := len(match[1])
+		switch {
+		case format == FormatUnixSeconds && digits >= 9 && digits <= 11:
+			seconds, fraction := math.Modf(value)
+			result.Time = time.Unix(int64(seconds), int64(math.Round(fraction*1e9))).UTC()
+		case format == FormatUnixMillis && digits >= 12 && digits <= 14 && match[2] == "":
+			result.Time = time.UnixMilli(int64(value)).UTC()
+		case format == FormatExcelSerial && digits <= 7 && value >= 1 && value < 2958466:
+			days, fraction := math.Modf(value)
+			sinceMidnight := time.Duration(math.Round(fraction*24*3600)) * time.Second
+			result.Time = TimeOfDayOf(time.Time{}.Add(sinceMidnight)).On(excelEpoch.AddDays(int(days)), loc)
+		default:
+			return result, false
+		}
+		return result, true
+
+	case FormatDayMonthYear, FormatMonthDayYear:
+		match := slashDateRegexp.FindStringSubmatch(s)
+		if match == nil {
+			return result, false
+		}
+		day, _ := strconv.Atoi(match[1])
+		month, _ := strconv.Atoi(match[2])
+		if format == FormatMonthDayYear {
+			day, month = month, day
+		}
+		year, _ := strconv.Atoi(match[3])
+		date := NewDate(year, time.Month(month), day)
+		if !date.IsValid() {
+			return result, false
+		}
+		result.Time = date.In(loc)
+		return result, true
+	}
+
+	return result, false
+}
+
+func parseLayouts(s string, layouts []string, loc *time.Location, result ParseResult) (ParseResult, bool) {
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			result.Time, result.Layout = t, layout
+			return result, true
+		}
+	}
+	return result, false
+}
+
+// parseRFCLayouts is parseLayouts for layouts starting with the day of the
+// week, which must be the one of the date, and ending in a zone name, which
+// must be one of rfc5322Zones.
+func parseRFCLayouts(s string, layouts []string, loc *time.Location, result ParseResult) (ParseResult, bool) {
+	weekday, _, _ := strings.Cut(s, ",")
+
+	zone := s[strings.LastIndexByte(s, ' ')+1:]
+	hours, named := rfc5322Zones[zone]
+	if named {
+		// time.Parse doesn't know "UT" and "Z"
+		s = s[:len(s)-len(zone)] + "GMT"
+	}
+
+	result, ok := parseLayouts(s, layouts, loc, result)
+	if !ok {
+		return result, false
+	}
+	if strings.HasSuffix(result.Layout, "MST") {
+		if !named {
+			return result, false
+		}
+		zoneLoc := time.UTC
+		if hours != 0 {
+			zoneLoc = time.FixedZone(zone, hours*3600)
+		}
+		result.Time = inLocation(result.Time, zoneLoc)
+	}
+
+	// time.Parse doesn't check the day of the week
+	day := result.Time.Weekday().String()
+	if !strings.EqualFold(weekday, day) && !strings.EqualFold(weekday, day[:3]) {
+		return result, false
+	}
+	return result, true
+}
+
+// inLocation returns the wall clock time of t in the location l.
+func inLocation(t time.Time, l *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), l)
+}
@@ -0,0 +1,159 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseAny(t *testing.T) {
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	require.Nil(t, err)
+
+	for _, test := range []struct {
+		from     string
+		format   InputFormat
+		expected string
+	}{
+		{"2024-02-14T09:00:00+01:00", FormatISO8601, "2024-02-14T09:00:00+01:00"},
+		{"2024-02-14T08:00:00.123Z", FormatISO8601, "2024-02-14T09:00:00+01:00"},
+		{"2024-02-14T09:00:00", FormatISO8601, "2024-02-14T09:00:00+01:00"},
+		{"2024-02-14", FormatISO8601, "2024-02-14T00:00:00+01:00"},
+		{"20240214T080000Z", FormatISO8601Basic, "2024-02-14T09:00:00+01:00"},
+		{"20240214T090000+0100", FormatISO8601Basic, "2024-02-14T09:00:00+01:00"},
+		{"20240214T0900", FormatISO8601Basic, "2024-02-14T09:00:00+01:00"},
+		{"20240214", FormatISO8601Basic, "2024-02-14T00:00:00+01:00"},
+		{"Wed, 14 Feb 2024 08:00:00 GMT", FormatRFC1123, "2024-02-14T09:00:00+01:00"},
+		{"Wed, 14 Feb 2024 08:00:00 UT", FormatRFC1123, "2024-02-14T09:00:00+01:00"},
+		{"Wed, 14 Feb 2024 03:00:00 EST", FormatRFC1123, "2024-02-14T09:00:00+01:00"},
+		{"Wed, 14 Feb 2024 00:00:00 PST", FormatRFC1123, "2024-02-14T09:00:00+01:00"},
+		{"Wed, 14 Feb 2024 09:00:00 +0100", FormatRFC1123, "2024-02-14T09:00:00+01:00"},
+		{"Wednesday, 14-Feb-24 08:00:00 GMT", FormatRFC850, "2024-02-14T09:00:00+01:00"},
+		{"Wednesday, 14-Feb-24 01:00:00 PDT", FormatRFC850, "2024-02-14T09:00:00+01:00"},
+		{"2024-02-14 09:00:00", FormatSQLDateTime, "2024-02-14T09:00:00+01:00"},
+		{"2024-02-14 09:00", FormatSQLDateTime, "2024-02-14T09:00:00+01:00"},
+		{"1707897600", FormatUnixSeconds, "2024-02-14T09:00:00+01:00"},
+		{"1707897600000", FormatUnixMillis, "2024-02-14T09:00:00+01:00"},
+		{"14/02/2024", FormatDayMonthYear, "2024-02-14T00:00:00+01:00"},
+		{"14.02.2024", FormatDayMonthYear, "2024-02-14T00:00:00+01:00"},
+		{"02/14/2024", FormatMonthDayYear, "2024-02-14T00:00:00+01:00"},
+		{"01/02/2024", FormatDayMonthYear, "2024-02-01T00:00:00+01:00"},
+	} {
+		t.Run(test.from, func(t *testing.T) {
+			result, err := ParseAny(test.from, ParseOptions{DefaultLocation: stockholm})
+			require.Nil(t, err)
+			require.Equal(t, test.format, result.Format)
+			require.Equal(t, test.expected, TimeToLocalISO8601DateTimeString(result.Time, stockholm))
+		})
+	}
+
+	// Without a default location zone-less input is in UTC
+	result, err := ParseAny("2024-02-14 09:00:00", ParseOptions{})
+	require.Nil(t, err)
+	require.Equal(t, "2024-02-14T09:00:00Z", TimeToISO8601DateTimeString(result.Time))
+	require.Equal(t, "2006-01-02 15:04:05.999999999", result.Layout)
+
+	_, err = ParseAny("not a date", ParseOptions{})
+	require.ErrorIs(t, err, ErrInvalidValue)
+	_, err = ParseAny("31/02/2024", ParseOptions{})
+	require.ErrorIs(t, err, ErrInvalidValue)
+
+	// Date only input reports the date layout
+	result, err = ParseAny("2024-02-14", ParseOptions{})
+	require.Nil(t, err)
+	require.Equal(t, ISO8601Date, result.Layout)
+
+	// Short numbers are not Excel serial dates unless asked for
+	_, err = ParseAny("2024", ParseOptions{})
+	require.ErrorIs(t, err, ErrInvalidValue)
+	for from, expected := range map[string]string{
+		"45336.375": "2024-02-14T09:00:00+01:00",
+		"45336":     "2024-02-14T00:00:00+01:00",
+	} {
+		result, err = ParseAny(from, ParseOptions{DefaultLocation: stockholm, Formats: []InputFormat{FormatExcelSerial}})
+		require.Nil(t, err)
+		require.Equal(t, FormatExcelSerial, result.Format)
+		require.Equal(t, expected, TimeToLocalISO8601DateTimeString(result.Time, stockholm))
+	}
+
+	// The day of the week must be the one of the date
+	_, err = ParseAny("Thu, 14 Feb 2024 08:00:00 GMT", ParseOptions{})
+	require.ErrorIs(t, err, ErrInvalidValue)
+	_, err = ParseAny("Thursday, 14-Feb-24 08:00:00 GMT", ParseOptions{})
+	require.ErrorIs(t, err, ErrInvalidValue)
+	_, err = ParseAny("Thu, 14 Feb 2024 23:30:00 -0100", ParseOptions{})
+	require.ErrorIs(t, err, ErrInvalidValue)
+
+	// Other zone abbreviations than those of RFC 5322 are ambiguous
+	_, err = ParseAny("Wed, 14 Feb 2024 09:00:00 CET", ParseOptions{})
+	require.ErrorIs(t, err, ErrInvalidValue)
+	_, err = ParseAny("Wednesday, 14-Feb-24 09:00:00 IST", ParseOptions{})
+	require.ErrorIs(t, err, ErrInvalidValue)
+}
+
+func Test_ParseAny_Formats(t *testing.T) {
+	_require := require.New(t)
+
+	_, err := ParseAny("1707897600", ParseOptions{Formats: []InputFormat{FormatISO8601, FormatRFC1123}})
+	_require.ErrorIs(err, ErrInvalidValue)
+
+	// Month first is preferred when it is the first allowed
+	result, err := ParseAny("01/02/2024", ParseOptions{Formats: []InputFormat{FormatMonthDayYear, FormatDayMonthYear}})
+	_require.Nil(err)
+	_require.Equal(FormatMonthDayYear, result.Format)
+	_require.Equal("2024-01-02", TimeToISO8601DateString(result.Time))
+
+	_require.Equal("Unix seconds", FormatUnixSeconds.String())
+	_require.Equal("InputFormat(0)", InputFormat(0).String())
+}
+
+func Test_ParseAny_RejectAmbiguous(t *testing.T) {
+	_require := require.New(t)
+
+	opts := ParseOptions{RejectAmbiguous: true}
+
+	_, err := ParseAny("01/02/2024", opts)
+	_require.ErrorIs(err, ErrInvalidValue)
+
+	result, err := ParseAny("13/02/2024", opts)
+	_require.Nil(err)
+	_require.Equal(FormatDayMonthYear, result.Format)
+
+	result, err = ParseAny("02/13/2024", opts)
+	_require.Nil(err)
+	_require.Equal(FormatMonthDayYear, result.Format)
+
+	// Day and month being the same is not ambiguous
+	result, err = ParseAny("05/05/2024", opts)
+	_require.Nil(err)
+	_require.Equal("2024-05-05", TimeToISO8601DateString(result.Time))
+
+	// Only allowing one of the orders is not ambiguous either
+	result, err = ParseAny("01/02/2024", ParseOptions{RejectAmbiguous: true, Formats: []InputFormat{FormatMonthDayYear}})
+	_require.Nil(err)
+	_require.Equal("2024-01-02", TimeToISO8601DateString(result.Time))
+}
+
+func Test_ParseAny_Strict(t *testing.T) {
+	_require := require.New(t)
+
+	opts := ParseOptions{Strict: true}
+
+	result, err := ParseAny("2024-02-14T09:00:00+01:00", opts)
+	_require.Nil(err)
+	_require.Equal(FormatISO8601, result.Format)
+	_require.Equal("2024-02-14T09:00:00+01:00", TimeToISO8601DateTimeString(result.Time))
+
+	for _, from := range []string{
+		"2024-02-14",
+		"2024-02-14T09:00:00",
+		"2024-02-14T09:00:00.5+01:00",
+		"2024-02-14T08:00:00+00:00",
+		"20240214T080000Z",
+		"1707897600",
+	} {
+		_, err := ParseAny(from, opts)
+		_require.ErrorIsf(err, ErrInvalidValue, from)
+	}
+}
@@ -0,0 +1,239 @@
+package datetime
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParsePOSIXTZ parses a POSIX TZ string, such as "CET-1CEST,M3.5.0,M10.5.0/3"
+// as reported by some devices, into a ZoneRule. Note that the POSIX offsets
+// are west of UTC, while ZoneRule offsets are east of UTC.
+//
+// DST rules on Julian days, "J60" or "59", and a DST offset of 0 but for
+// StdOffset -1h, such as the negative DST of Europe/Dublin,
+// "IST-1GMT0,M10.5.0,M3.5.0/1", are parsed by LocationFromPOSIXTZ but can't be
+// returned as a ZoneRule.
+func ParsePOSIXTZ(tz string) (ZoneRule, error) {
+	rule, unsupported, err := parsePOSIXTZ(tz)
+	if err != nil {
+		return ZoneRule{}, err
+	}
+	if unsupported != "" {
+		return ZoneRule{}, fmt.Errorf("%w: POSIX TZ %q %s", ErrInvalidValue, tz, unsupported)
+	}
+	return rule, nil
+}
+
+// LocationFromPOSIXTZ returns the location of a POSIX TZ string, named tz. A
+// string starting with ":", such as ":Europe/Stockholm", is loaded with
+// LoadLocation.
+func LocationFromPOSIXTZ(tz string) (*time.Location, error) {
+	tz = strings.TrimSpace(tz)
+	if name, ok := strings.CutPrefix(tz, ":"); ok {
+		l, err := LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidValue, name)
+		}
+		return l, nil
+	}
+
+	rule, _, err := parsePOSIXTZ(tz)
+	if err != nil {
+		return nil, err
+	}
+	return time.LoadLocationFromTZData(tz, tzifData(rule.StdName, rule.StdOffset, tz))
+}
+
+// LocationFromTZif returns the location of TZif data, the format of the
+// files of /usr/share/zoneinfo, named name.
+func LocationFromTZif(name string, data []byte) (*time.Location, error) {
+	if !bytes.HasPrefix(data, []byte("TZif")) {
+		return nil, fmt.Errorf("%w: %q is not TZif data", ErrInvalidValue, name)
+	}
+	l, err := time.LoadLocationFromTZData(name, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid TZif data of %q: %v", ErrInvalidValue, name, err)
+	}
+	return l, nil
+}
+
+// parsePOSIXTZ parses tz, unsupported is why tz can't be a ZoneRule, such as
+// when the DST rules are on Julian days and so not set in the rule.
+func parsePOSIXTZ(tz string) (rule ZoneRule, unsupported string, err error) {
+	p := &posixTZParser{s: tz}
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("%w: invalid POSIX TZ %q: %v", ErrInvalidValue, tz, err)
+		}
+	}()
+
+	if rule.StdName, err = p.name(); err != nil {
+		return rule, "", err
+	}
+	offset, err := p.offset(24)
+	if err != nil {
+		return rule, "", err
+	}
+	rule.StdOffset = -offset
+	if p.done() {
+		return rule, "", nil
+	}
+
+	if rule.DSTName, err = p.name(); err != nil {
+		return rule, "", err
+	}
+	if !p.done() && p.peek() != ',' {
+		if offset, err = p.offset(24); err != nil {
+			return rule, "", err
+		}
+		rule.DSTOffset = -offset
+		if rule.DSTOffset == 0 && rule.StdOffset != -time.Hour {
+			// ZoneRule uses 0 for the default DST offset
+			unsupported = "has a DST offset of 0"
+		}
+	}
+	if p.done() {
+		return rule, "", fmt.Errorf("the DST rules are missing")
+	}
+
+	julian := false
+	for _, date := range []*ZoneRuleDate{&rule.DSTStart, &rule.DSTEnd} {
+		if !p.consume(',') {
+			return rule, "", fmt.Errorf("expected ',' at %d", p.i)
+		}
+		isJulian, err := p.date(date)
+		if err != nil {
+			return rule, "", err
+		}
+		julian = julian || isJulian
+		date.At = 2 * time.Hour
+		if p.consume('/') {
+			if date.At, err = p.offset(167); err != nil {
+				return rule, "", err
+			}
+		}
+	}
+	if !p.done() {
+		return rule, "", fmt.Errorf("unexpected %q at %d", p.s[p.i:], p.i)
+	}
+	if julian {
+		rule.DSTStart, rule.DSTEnd = ZoneRuleDate{}, ZoneRuleDate{}
+		unsupported = "has DST rules on Julian days"
+	}
+	return rule, unsupported, nil
+}
+
+type posixTZParser struct {
+	s string
+	i int
+}
+
+func (p *posixTZParser) done() bool {
+	return p.i >= len(p.s)
+}
+
+func (p *posixTZParser) peek() byte {
+	return p.s[p.i]
+}
+
+func (p *posixTZParser) consume(c byte) bool {
+	if !p.done() && p.peek() == c {
+		p.i++
+		return true
+	}
+	return false
+}
+
+// name parses a zone abbreviation, "CET" or quoted, "<+0530>".
+func (p *posixTZParser) name() (string, error) {
+	start := p.i
+	if p.consume('<') {
+		for !p.done() && (isPOSIXLetter(p.peek()) || isPOSIXDigit(p.peek()) || p.peek() == '+' || p.peek() == '-') {
+			p.i++
+		}
+		name := p.s[start+1 : p.i]
+		if !p.consume('>') || len(name) < 3 {
+			return "", fmt.Errorf("invalid quoted zone name at %d", start)
+		}
+		return name, nil
+	}
+	for !p.done() && isPOSIXLetter(p.peek()) {
+		p.i++
+	}
+	if p.i-start < 3 {
+		return "", fmt.Errorf("zone name at %d must be at least 3 letters", start)
+	}
+	return p.s[start:p.i], nil
+}
+
+// offset parses [+-]hh[:mm[:ss]], with at most maxHours hours.
+func (p *posixTZParser) offset(maxHours int) (time.Duration, error) {
+	start := p.i
+	sign := time.Duration(1)
+	if p.consume('-') {
+		sign = -1
+	} else {
+		p.consume('+')
+	}
+
+	var d time.Duration
+	for k, limit := range []int{maxHours, 59, 59} {
+		if k > 0 && !p.consume(':') {
+			break
+		}
+		n, ok := p.number(3)
+		if !ok || n > limit {
+			return 0, fmt.Errorf("invalid offset or time at %d", start)
+		}
+		d += time.Duration(n) * []time.Duration{time.Hour, time.Minute, time.Second}[k]
+	}
+	return sign * d, nil
+}
+
+// date parses Mm.w.d into date, or a Julian day, Jn or n, then julian is true.
+func (p *posixTZParser) date(date *ZoneRuleDate) (julian bool, err error) {
+	start := p.i
+	if p.consume('M') {
+		month, ok1 := p.number(2)
+		ok2 := p.consume('.')
+		week, ok3 := p.number(1)
+		ok4 := p.consume('.')
+		weekday, ok5 := p.number(1)
+		if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 || month < 1 || month > 12 || week < 1 || week > 5 || weekday > 6 {
+			return false, fmt.Errorf("invalid month rule at %d", start)
+		}
+		date.Month, date.Week, date.Weekday = time.Month(month), week, time.Weekday(weekday)
+		return false, nil
+	}
+
+	firstDay := 0
+	if p.consume('J') {
+		firstDay = 1
+	}
+	day, ok := p.number(3)
+	if !ok || day < firstDay || day > 365 {
+		return false, fmt.Errorf("invalid day rule at %d", start)
+	}
+	return true, nil
+}
+
+// number parses up to maxDigits decimal digits.
+func (p *posixTZParser) number(maxDigits int) (int, bool) {
+	n, digits := 0, 0
+	for !p.done() && isPOSIXDigit(p.peek()) && digits < maxDigits {
+		n = n*10 + int(p.peek()-'0')
+		p.i++
+		digits++
+	}
+	return n, digits > 0
+}
+
+func isPOSIXLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isPOSIXDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,133 @@
+package datetime
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// requireSameOffsets verifies that l has the same offsets and abbreviations as
+// the IANA zone, every 3 hours of the years.
+func requireSameOffsets(t *testing.T, l *time.Location, zoneID string, fromYear, toYear int) {
+	iana, err := time.LoadLocation(zoneID)
+	require.Nil(t, err)
+	for tm := time.Date(fromYear, 1, 1, 0, 0, 0, 0, time.UTC); tm.Year() < toYear; tm = tm.Add(3 * time.Hour) {
+		name, offset := tm.In(l).Zone()
+		expectedName, expectedOffset := tm.In(iana).Zone()
+		require.Equal(t, expectedOffset, offset, "%s at %s", zoneID, tm)
+		require.Equal(t, expectedName, name, "%s at %s", zoneID, tm)
+	}
+}
+
+func Test_LocationFromPOSIXTZ(t *testing.T) {
+	for tz, zoneID := range map[string]string{
+		"CET-1CEST,M3.5.0,M10.5.0/3":           "Europe/Stockholm",
+		"EET-2EEST,M3.5.0/3,M10.5.0/4":         "Europe/Helsinki",
+		"GMT0BST,M3.5.0/1,M10.5.0":             "Europe/London",
+		"EST5EDT,M3.2.0,M11.1.0":               "America/New_York",
+		"AEST-10AEDT,M10.1.0,M4.1.0/3":         "Australia/Sydney",
+		"<-01>1<+00>,M3.5.0/0,M10.5.0/1":       "Atlantic/Azores",
+		"NZST-12NZDT,M9.5.0,M4.1.0/3":          "Pacific/Auckland",
+		"<-02>2<-01>,M3.5.0/-1,M10.5.0/0":      "America/Nuuk",
+		"IST-5:30":                             "Asia/Kolkata",
+		"<+0545>-5:45":                         "Asia/Kathmandu",
+		"NST3:30NDT,M3.2.0,M11.1.0":            "America/St_Johns",
+		"<+1030>-10:30<+11>-11,M10.1.0,M4.1.0": "Australia/Lord_Howe",
+		"IST-1GMT0,M10.5.0,M3.5.0/1":           "Europe/Dublin",
+	} {
+		l, err := LocationFromPOSIXTZ(tz)
+		require.Nil(t, err, tz)
+		require.Equal(t, tz, l.String())
+		requireSameOffsets(t, l, zoneID, 2024, 2030)
+	}
+
+	l, err := LocationFromPOSIXTZ(":Europe/Stockholm")
+	require.Nil(t, err)
+	require.Equal(t, "Europe/Stockholm", l.String())
+
+	// Julian days, DST from March 1 to November 1 in years without leap days
+	l, err = LocationFromPOSIXTZ("XST-1XDT,J60/2,J305/3")
+	require.Nil(t, err)
+	_, offset := time.Date(2023, 3, 1, 12, 0, 0, 0, l).Zone()
+	require.Equal(t, 7200, offset)
+	_, offset = time.Date(2023, 2, 28, 12, 0, 0, 0, l).Zone()
+	require.Equal(t, 3600, offset)
+}
+
+func Test_LocationFromPOSIXTZ_Converters(t *testing.T) {
+	l, err := LocationFromPOSIXTZ("CET-1CEST,M3.5.0,M10.5.0/3")
+	require.Nil(t, err)
+
+	tm := time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC)
+	require.Equal(t, "2024-07-01T12:00:00+02:00", TimeToLocalISO8601DateTimeString(tm, l))
+
+	proto := TimeToProtoDateTime(tm.In(l))
+	require.Equal(t, "CET-1CEST,M3.5.0,M10.5.0/3", proto.GetTimeZone().GetId())
+	back, err := ProtoDateTimeToTimeWithResolver(proto, LenientZoneResolver)
+	require.Nil(t, err)
+	require.True(t, tm.Equal(back))
+}
+
+func Test_ParsePOSIXTZ(t *testing.T) {
+	rule, err := ParsePOSIXTZ("CET-1CEST,M3.5.0,M10.5.0/3")
+	require.Nil(t, err)
+	require.Equal(t, stockholmRule, rule)
+
+	for _, tz := range []string{
+		"CET-1CEST,M3.5.0,M10.5.0/3",
+		"<-01>1<+00>,M3.5.0/0,M10.5.0/1",
+		"<-03>3<-02>,M3.5.0/-2,M10.5.0/-1",
+		"<+1030>-10:30<+11>-11,M10.1.0,M4.1.0",
+		"IST-5:30",
+	} {
+		rule, err := ParsePOSIXTZ(tz)
+		require.Nil(t, err, tz)
+		require.Equal(t, tz, rule.String())
+	}
+
+	_, err = ParsePOSIXTZ("XST-1XDT,J60/2,J305/3")
+	require.ErrorIs(t, err, ErrInvalidValue)
+	_, err = ParsePOSIXTZ("IST-1GMT0,M10.5.0,M3.5.0/1")
+	require.ErrorIs(t, err, ErrInvalidValue)
+
+	for _, tz := range []string{
+		"",
+		"CE-1",
+		"CET",
+		"CET-25",
+		"CET-1CEST",
+		"CET-1CEST,M3.5.0",
+		"CET-1CEST,M13.5.0,M10.5.0",
+		"CET-1CEST,M3.6.0,M10.5.0",
+		"CET-1CEST,M3.5.7,M10.5.0",
+		"CET-1CEST,M3.5.0,M10.5.0/168",
+		"CET-1CEST,M3.5.0,M10.5.0/3x",
+		"<+1>-1",
+		"XST-1XDT,J0,J305",
+	} {
+		_, err := LocationFromPOSIXTZ(tz)
+		require.ErrorIs(t, err, ErrInvalidValue, tz)
+	}
+}
+
+func Test_LocationFromTZif(t *testing.T) {
+	l, err := LocationFromTZif("Test/Stockholm", tzifFromRule(stockholmRule))
+	require.Nil(t, err)
+	requireSameOffsets(t, l, "Europe/Stockholm", 1996, 2040)
+
+	_, err = LocationFromTZif("Test/Broken", []byte("TZif2 but not really"))
+	require.ErrorIs(t, err, ErrInvalidValue)
+	_, err = LocationFromTZif("Test/Broken", []byte("CET-1"))
+	require.ErrorIs(t, err, ErrInvalidValue)
+
+	// A zone file of the host, with transitions
+	data, err := os.ReadFile("/usr/share/zoneinfo/Europe/Stockholm")
+	if err != nil {
+		t.Skip("no zoneinfo on the host")
+	}
+	l, err = LocationFromTZif("Europe/Stockholm", data)
+	require.Nil(t, err)
+	requireSameOffsets(t, l, "Europe/Stockholm", 1970, 2040)
+}
@@ -0,0 +1,222 @@
+package datetime
+
+import (
+	"container/list"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	dtpb "google.golang.org/genproto/googleapis/type/datetime"
+)
+
+// SkewEstimatorOptions configures a SkewEstimator.
+type SkewEstimatorOptions struct {
+	// TimeProvider is the local clock, NewUTCTimeProvider if nil
+	TimeProvider TimeProvider
+	// Window is the number of the last samples of a peer the statistics are
+	// computed from, 64 if 0
+	Window int
+	// Threshold is the skew, ahead or behind, above which a peer is flagged,
+	// 1 minute if 0
+	Threshold time.Duration
+	// MinSamples is the number of samples of a peer before it can be
+	// flagged, 5 if 0
+	MinSamples int
+	// MaxPeers is the number of peers tracked, the peer seen least recently
+	// is forgotten for a new one, 10000 if 0
+	MaxPeers int
+}
+
+// SkewStats are the skew statistics of a peer.
+type SkewStats struct {
+	Peer string
+	// Samples is the number of samples in the window
+	Samples int
+	// Median is the median skew of the samples, positive if the clock of the
+	// peer is ahead of the local clock
+	Median time.Duration
+	// MAD is the median absolute deviation of the skews from Median, how much
+	// they vary, not scaled to a standard deviation
+	MAD time.Duration
+	// LastSeen is the local time of the last sample
+	LastSeen time.Time
+	// Flagged is true if the peer has at least MinSamples samples and the
+	// median skew is above Threshold
+	Flagged bool
+}
+
+type skewPeer struct {
+	skews    []time.Duration // ring buffer of the last Window samples
+	next     int
+	lastSeen time.Time
+	recent   *list.Element // of the peer in SkewEstimator.recent
+}
+
+// SkewEstimator estimates the clock skew of peers, such as clients sending
+// timestamps, from the timestamps they send and the local time they are
+// received. The median and MAD of the last samples are robust to delayed
+// messages and outliers. Note that network latency makes peers appear to be
+// behind by the latency. It is safe for concurrent use.
+//
+//	skew := estimator.Observe(clientID, request.SentAt)
+//	if stats, _ := estimator.Stats(clientID); stats.Flagged {
+//		log.Printf("clock of %s is %s off", clientID, stats.Median)
+//	}
+//	startsAt := estimator.Correct(clientID, request.StartsAt)
+type SkewEstimator struct {
+	opts   SkewEstimatorOptions
+	mu     sync.Mutex
+	peers  map[string]*skewPeer
+	recent *list.List // of the peers, the most recently seen first
+}
+
+// NewSkewEstimator returns an estimator without samples.
+func NewSkewEstimator(opts SkewEstimatorOptions) *SkewEstimator {
+	if opts.TimeProvider == nil {
+		opts.TimeProvider = NewUTCTimeProvider()
+	}
+	if opts.Window <= 0 {
+		opts.Window = 64
+	}
+	if opts.Threshold <= 0 {
+		opts.Threshold = time.Minute
+	}
+	if opts.MinSamples <= 0 {
+		opts.MinSamples = 5
+	}
+	if opts.MaxPeers <= 0 {
+		opts.MaxPeers = 10000
+	}
+	return &SkewEstimator{opts: opts, peers: make(map[string]*skewPeer), recent: list.New()}
+}
+
+// Observe records a timestamp sent by peer, received now, and returns its
+// skew from the local time.
+func (e *SkewEstimator) Observe(peer string, remote time.Time) time.Duration {
+	now := e.opts.TimeProvider.Now()
+	skew := remote.Sub(now)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	p, ok := e.peers[peer]
+	if ok {
+		e.recent.MoveToFront(p.recent)
+	} else {
+		if len(e.peers) >= e.opts.MaxPeers {
+			e.forget(e.recent.Back().Value.(string))
+		}
+		p = &skewPeer{recent: e.recent.PushFront(peer)}
+		e.peers[peer] = p
+	}
+	if len(p.skews) < e.opts.Window {
+		p.skews = append(p.skews, skew)
+	} else {
+		p.skews[p.next] = skew
+	}
+	p.next = (p.next + 1) % e.opts.Window
+	p.lastSeen = now
+	return skew
+}
+
+// ObserveProtoDateTime is like Observe for a google.type.DateTime, as
+// converted by ProtoDateTimeToTime.
+func (e *SkewEstimator) ObserveProtoDateTime(peer string, remote *dtpb.DateTime) (time.Duration, error) {
+	t, err := ProtoDateTimeToTime(remote)
+	if err != nil {
+		return 0, err
+	}
+	return e.Observe(peer, t), nil
+}
+
+// ObserveISO8601 is like Observe for an ISO8601 string.
+func (e *SkewEstimator) ObserveISO8601(peer string, remote string) (time.Duration, error) {
+	t, err := ISO8601StringToTime(remote)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidValue, err)
+	}
+	return e.Observe(peer, t), nil
+}
+
+// Stats returns the skew statistics of peer, and false if it has no samples.
+func (e *SkewEstimator) Stats(peer string) (SkewStats, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	p, ok := e.peers[peer]
+	if !ok {
+		return SkewStats{}, false
+	}
+	return e.stats(peer, p), true
+}
+
+// Flagged returns the statistics of the flagged peers, by peer.
+func (e *SkewEstimator) Flagged() []SkewStats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var flagged []SkewStats
+	for peer, p := range e.peers {
+		if stats := e.stats(peer, p); stats.Flagged {
+			flagged = append(flagged, stats)
+		}
+	}
+	sort.Slice(flagged, func(i, j int) bool { return flagged[i].Peer < flagged[j].Peer })
+	return flagged
+}
+
+// Correct returns remote, a time sent by peer, corrected by the median skew
+// of peer if it is flagged, and remote unchanged otherwise, so that the times
+// of peers within the threshold keep their precision.
+func (e *SkewEstimator) Correct(peer string, remote time.Time) time.Time {
+	if stats, ok := e.Stats(peer); ok && stats.Flagged {
+		return remote.Add(-stats.Median)
+	}
+	return remote
+}
+
+// Forget removes the samples of peer.
+func (e *SkewEstimator) Forget(peer string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.forget(peer)
+}
+
+// forget removes peer, the caller must hold e.mu.
+func (e *SkewEstimator) forget(peer string) {
+	if p, ok := e.peers[peer]; ok {
+		e.recent.Remove(p.recent)
+		delete(e.peers, peer)
+	}
+}
+
+func (e *SkewEstimator) stats(peer string, p *skewPeer) SkewStats {
+	median := medianDuration(p.skews)
+	deviations := make([]time.Duration, len(p.skews))
+	for i, skew := range p.skews {
+		deviations[i] = (skew - median).Abs()
+	}
+
+	stats := SkewStats{
+		Peer:     peer,
+		Samples:  len(p.skews),
+		Median:   median,
+		MAD:      medianDuration(deviations),
+		LastSeen: p.lastSeen,
+	}
+	stats.Flagged = stats.Samples >= e.opts.MinSamples && median.Abs() > e.opts.Threshold
+	return stats
+}
+
+// medianDuration returns the median of durations, the mean of the middle two
+// for an even number.
+func medianDuration(durations []time.Duration) time.Duration {
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	}
+	return sorted[middle-1] + (sorted[middle]-sorted[middle-1])/2
+}
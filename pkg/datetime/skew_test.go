@@ -0,0 +1,155 @@
+package datetime
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	dtpb "google.golang.org/genproto/googleapis/type/datetime"
+)
+
+func Test_SkewEstimator(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewManualTimeProvider(decoratorsNow)
+	e := NewSkewEstimator(SkewEstimatorOptions{TimeProvider: tp, MinSamples: 3})
+
+	_, ok := e.Stats("clinic-1")
+	_require.False(ok)
+
+	// A client 3 minutes ahead, with latency and an outlier delayed message
+	for _, skew := range []time.Duration{
+		3*time.Minute - 100*time.Millisecond,
+		3*time.Minute - 300*time.Millisecond,
+		-time.Hour,
+		3*time.Minute - 200*time.Millisecond,
+	} {
+		tp.Advance(time.Second)
+		_require.Equal(skew, e.Observe("clinic-1", tp.Now().Add(skew)))
+	}
+	stats, ok := e.Stats("clinic-1")
+	_require.True(ok)
+	_require.Equal(SkewStats{
+		Peer:     "clinic-1",
+		Samples:  4,
+		Median:   3*time.Minute - 250*time.Millisecond,
+		MAD:      100 * time.Millisecond,
+		LastSeen: tp.Now(),
+		Flagged:  true,
+	}, stats)
+
+	// Corrected by the median
+	_require.Equal(decoratorsNow.Add(250*time.Millisecond), e.Correct("clinic-1", decoratorsNow.Add(3*time.Minute)))
+
+	// A client within the threshold isn't flagged nor corrected
+	for i := 0; i < 5; i++ {
+		e.Observe("clinic-2", tp.Now().Add(-20*time.Second))
+	}
+	stats, _ = e.Stats("clinic-2")
+	_require.Equal(-20*time.Second, stats.Median)
+	_require.Equal(time.Duration(0), stats.MAD)
+	_require.False(stats.Flagged)
+	_require.Equal(decoratorsNow, e.Correct("clinic-2", decoratorsNow))
+
+	// A client with too few samples isn't flagged
+	e.Observe("clinic-3", tp.Now().Add(-time.Hour))
+	e.Observe("clinic-3", tp.Now().Add(-time.Hour))
+	_require.Equal(decoratorsNow, e.Correct("clinic-3", decoratorsNow))
+	e.Observe("clinic-3", tp.Now().Add(-time.Hour))
+
+	flagged := e.Flagged()
+	_require.Len(flagged, 2)
+	_require.Equal("clinic-1", flagged[0].Peer)
+	_require.Equal("clinic-3", flagged[1].Peer)
+	_require.Equal(-time.Hour, flagged[1].Median)
+
+	e.Forget("clinic-3")
+	_, ok = e.Stats("clinic-3")
+	_require.False(ok)
+	_require.Len(e.Flagged(), 1)
+}
+
+func Test_SkewEstimator_Window(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewManualTimeProvider(decoratorsNow)
+	e := NewSkewEstimator(SkewEstimatorOptions{TimeProvider: tp, Window: 4, MaxPeers: 2})
+
+	// The clock of the client is corrected, the old samples leave the window
+	for i := 0; i < 4; i++ {
+		e.Observe("clinic-1", tp.Now().Add(10*time.Minute))
+	}
+	for i := 0; i < 3; i++ {
+		e.Observe("clinic-1", tp.Now())
+	}
+	stats, _ := e.Stats("clinic-1")
+	_require.Equal(4, stats.Samples)
+	_require.Equal(time.Duration(0), stats.Median)
+
+	// The peer seen least recently is forgotten
+	tp.Advance(time.Second)
+	e.Observe("clinic-2", tp.Now())
+	tp.Advance(time.Second)
+	e.Observe("clinic-1", tp.Now())
+	tp.Advance(time.Second)
+	e.Observe("clinic-3", tp.Now())
+	_, ok := e.Stats("clinic-2")
+	_require.False(ok)
+	_, ok = e.Stats("clinic-1")
+	_require.True(ok)
+
+	// A forgotten peer frees its place
+	e.Forget("clinic-1")
+	e.Observe("clinic-4", tp.Now())
+	e.Observe("clinic-3", tp.Now())
+	e.Observe("clinic-5", tp.Now())
+	_, ok = e.Stats("clinic-4")
+	_require.False(ok)
+	_, ok = e.Stats("clinic-3")
+	_require.True(ok)
+}
+
+func Test_SkewEstimator_Observe(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewFakeTimeProvider(decoratorsNow)
+	e := NewSkewEstimator(SkewEstimatorOptions{TimeProvider: tp})
+
+	skew, err := e.ObserveISO8601("clinic-1", "2024-02-14T09:02:00+01:00")
+	_require.Nil(err)
+	_require.Equal(2*time.Minute-123456789, skew)
+
+	skew, err = e.ObserveProtoDateTime("clinic-1", &dtpb.DateTime{
+		Year: 2024, Month: 2, Day: 14, Hours: 9, Minutes: 2,
+		TimeOffset: &dtpb.DateTime_TimeZone{TimeZone: &dtpb.TimeZone{Id: "Europe/Stockholm"}},
+	})
+	_require.Nil(err)
+	_require.Equal(2*time.Minute-123456789, skew)
+
+	_, err = e.ObserveISO8601("clinic-1", "yesterday")
+	_require.ErrorIs(err, ErrInvalidValue)
+	_, err = e.ObserveProtoDateTime("clinic-1", nil)
+	_require.ErrorIs(err, ErrInvalidValue)
+
+	stats, _ := e.Stats("clinic-1")
+	_require.Equal(2, stats.Samples)
+
+	// Concurrently
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				peer := fmt.Sprint("clinic-", j%10)
+				e.Observe(peer, decoratorsNow.Add(time.Duration(i)*time.Minute))
+				e.Stats(peer)
+				e.Correct(peer, decoratorsNow)
+			}
+			e.Flagged()
+		}(i)
+	}
+	wg.Wait()
+}
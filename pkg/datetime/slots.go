@@ -0,0 +1,193 @@
+package datetime
+
+import (
+	"fmt"
+	"time"
+)
+
+// Interval is the half-open span of time [Start, End).
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// NewInterval returns the interval [start, end).
+func NewInterval(start, end time.Time) Interval {
+	return Interval{Start: start, End: end}
+}
+
+// Duration returns the length of i, 0 if i is empty.
+func (i Interval) Duration() time.Duration {
+	if i.IsEmpty() {
+		return 0
+	}
+	return i.End.Sub(i.Start)
+}
+
+// IsEmpty reports whether i has no length.
+func (i Interval) IsEmpty() bool {
+	return !i.Start.Before(i.End)
+}
+
+// Contains reports whether t is within i.
+func (i Interval) Contains(t time.Time) bool {
+	return !t.Before(i.Start) && t.Before(i.End)
+}
+
+// Overlaps reports whether i and other have any time in common.
+func (i Interval) Overlaps(other Interval) bool {
+	return i.Start.Before(other.End) && other.Start.Before(i.End)
+}
+
+// String returns i as an ISO8601 time interval, "start/end".
+func (i Interval) String() string {
+	return TimeToISO8601DateTimeString(i.Start) + "/" + TimeToISO8601DateTimeString(i.End)
+}
+
+// TimeOfDayRange is the half-open wall clock span [Start, End) within a day,
+// such as the opening hours 08:00-12:00.
+type TimeOfDayRange struct {
+	Start TimeOfDay
+	End   TimeOfDay
+}
+
+// NewTimeOfDayRange returns the wall clock span [start, end).
+func NewTimeOfDayRange(start, end TimeOfDay) TimeOfDayRange {
+	return TimeOfDayRange{Start: start, End: end}
+}
+
+// On returns the instants of r on the date d in the location l.
+func (r TimeOfDayRange) On(d Date, l *time.Location) Interval {
+	return Interval{Start: r.Start.On(d, l), End: r.End.On(d, l)}
+}
+
+// WeeklySchedule are the opening hours of each day of the week.
+type WeeklySchedule map[time.Weekday][]TimeOfDayRange
+
+// ScheduleException replaces the weekly opening hours of a date, such as a
+// public holiday with no Hours at all.
+type ScheduleException struct {
+	Date  Date
+	Hours []TimeOfDayRange
+}
+
+// SlotOptions configures GenerateSlots.
+type SlotOptions struct {
+	// Location of the opening hours, UTC if nil
+	Location *time.Location
+	Schedule WeeklySchedule
+	// Exceptions replace the Schedule of their dates
+	Exceptions []ScheduleException
+	// Busy are the existing appointments, in any order
+	Busy []Interval
+	// SlotLength is the length of each slot
+	SlotLength time.Duration
+	// Buffer is the free time required before and after busy intervals
+	Buffer time.Duration
+	// Alignment of the slot starts on the wall clock from midnight, such as
+	// every 15 minutes, SlotLength if 0
+	Alignment time.Duration
+	// TimeProvider is used to skip slots starting before Now, if set
+	TimeProvider TimeProvider
+}
+
+// GenerateSlots returns the available [start, end) slots, in order, on the
+// dates from up to but not including to.
+//
+// A slot is available when it is within the opening hours and does not
+// overlap any busy interval extended by the buffer. The opening hours and
+// the alignment follow the wall clock, so the slots stay at the same local
+// times across DST changes. A slot starting at a wall clock time that does
+// not exist, in a DST gap, is skipped, and a wall clock time that happens
+// twice, when the clocks are turned back, is only one slot.
+func GenerateSlots(opts SlotOptions, from, to Date) ([]Interval, error) {
+	var slots []Interval
+	err := EachSlot(opts, from, to, func(slot Interval) bool {
+		slots = append(slots, slot)
+		return true
+	})
+	return slots, err
+}
+
+// EachSlot is like GenerateSlots but calls yield for each slot, until yield
+// returns false. The opening hours of a day must not overlap.
+func EachSlot(opts SlotOptions, from, to Date, yield func(Interval) bool) error {
+	if opts.SlotLength <= 0 {
+		return fmt.Errorf("%w: slot length must be positive", ErrInvalidValue)
+	}
+	if opts.Alignment < 0 || opts.Buffer < 0 {
+		return fmt.Errorf("%w: alignment and buffer must not be negative", ErrInvalidValue)
+	}
+
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	alignment := opts.Alignment
+	if alignment == 0 {
+		alignment = opts.SlotLength
+	}
+
+	exceptions := make(map[Date][]TimeOfDayRange, len(opts.Exceptions))
+	for _, exception := range opts.Exceptions {
+		exceptions[exception.Date] = exception.Hours
+	}
+
+	busy := make([]Interval, 0, len(opts.Busy))
+	for _, i := range opts.Busy {
+		busy = append(busy, Interval{Start: i.Start.Add(-opts.Buffer), End: i.End.Add(opts.Buffer)})
+	}
+	busySet := NewIntervalSet(busy...)
+
+	var now time.Time
+	if opts.TimeProvider != nil {
+		now = opts.TimeProvider.Now()
+	}
+
+	for date := from; date.Before(to); date = date.AddDays(1) {
+		hours, ok := exceptions[date]
+		if !ok {
+			hours = opts.Schedule[date.Weekday()]
+		}
+
+		for i, r := range hours {
+			if r.End.Compare(r.Start) <= 0 {
+				return fmt.Errorf("%w: opening hours %s-%s on %s end before they start", ErrInvalidValue, r.Start, r.End, date)
+			}
+			for _, other := range hours[:i] {
+				if r.Start.Compare(other.End) < 0 && other.Start.Compare(r.End) < 0 {
+					return fmt.Errorf("%w: opening hours %s-%s and %s-%s on %s overlap", ErrInvalidValue, other.Start, other.End, r.Start, r.End, date)
+				}
+			}
+		}
+
+		for _, r := range hours {
+			open := r.On(date, loc)
+
+			// The first aligned wall clock time at or after the opening
+			wall := r.Start.SinceMidnight()
+			if rest := wall % alignment; rest != 0 {
+				wall += alignment - rest
+			}
+
+			for ; wall < 24*time.Hour; wall += alignment {
+				start := time.Date(date.Year, date.Month, date.Day, 0, 0, 0, int(wall), loc)
+				if TimeOfDayOf(start).SinceMidnight() != wall || DateOf(start) != date {
+					continue // not an existing wall clock time
+				}
+				slot := Interval{Start: start, End: start.Add(opts.SlotLength)}
+				if slot.End.After(open.End) {
+					break
+				}
+				if slot.Start.Before(open.Start) || slot.Start.Before(now) || busySet.Overlaps(slot) {
+					continue
+				}
+				if !yield(slot) {
+					return nil
+				}
+			}
+		}
+	}
+
+	return nil
+}
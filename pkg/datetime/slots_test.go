@@ -0,0 +1,215 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func slotStrings(slots []Interval, l *time.Location) []string {
+	formatted := make([]string, 0, len(slots))
+	for _, slot := range slots {
+		formatted = append(formatted, slot.Start.In(l).Format("2006-01-02 15:04")+"-"+slot.End.In(l).Format("15:04"))
+	}
+	return formatted
+}
+
+func officeHours(ranges ...string) []TimeOfDayRange {
+	hours := make([]TimeOfDayRange, 0, len(ranges)/2)
+	for i := 0; i < len(ranges); i += 2 {
+		start, _ := ParseTimeOfDay(ranges[i])
+		end, _ := ParseTimeOfDay(ranges[i+1])
+		hours = append(hours, NewTimeOfDayRange(start, end))
+	}
+	return hours
+}
+
+func Test_GenerateSlots(t *testing.T) {
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	require.Nil(t, err)
+
+	opts := SlotOptions{
+		Location: stockholm,
+		Schedule: WeeklySchedule{
+			time.Wednesday: officeHours("08:00", "10:00", "13:00", "14:00"),
+			time.Thursday:  officeHours("08:10", "09:30"),
+		},
+		SlotLength: 30 * time.Minute,
+	}
+
+	// Wednesday 2024-02-14 and Thursday 2024-02-15
+	slots, err := GenerateSlots(opts, NewDate(2024, 2, 14), NewDate(2024, 2, 16))
+	require.Nil(t, err)
+	require.Equal(t, []string{
+		"2024-02-14 08:00-08:30",
+		"2024-02-14 08:30-09:00",
+		"2024-02-14 09:00-09:30",
+		"2024-02-14 09:30-10:00",
+		"2024-02-14 13:00-13:30",
+		"2024-02-14 13:30-14:00",
+		"2024-02-15 08:30-09:00", // aligned to every 30 minutes
+		"2024-02-15 09:00-09:30",
+	}, slotStrings(slots, stockholm))
+
+	// Busy intervals with a buffer, aligned every 15 minutes
+	opts.Busy = []Interval{
+		NewInterval(time.Date(2024, 2, 14, 8, 30, 0, 0, stockholm), time.Date(2024, 2, 14, 9, 0, 0, 0, stockholm)),
+		NewInterval(time.Date(2024, 2, 14, 13, 0, 0, 0, stockholm), time.Date(2024, 2, 14, 13, 15, 0, 0, stockholm)),
+	}
+	opts.Buffer = 5 * time.Minute
+	opts.Alignment = 15 * time.Minute
+	slots, err = GenerateSlots(opts, NewDate(2024, 2, 14), NewDate(2024, 2, 15))
+	require.Nil(t, err)
+	require.Equal(t, []string{
+		"2024-02-14 09:15-09:45",
+		"2024-02-14 09:30-10:00",
+		"2024-02-14 13:30-14:00",
+	}, slotStrings(slots, stockholm))
+
+	// Exceptions replace the weekly schedule
+	opts.Busy, opts.Buffer, opts.Alignment = nil, 0, 0
+	opts.Exceptions = []ScheduleException{
+		{Date: NewDate(2024, 2, 14)}, // closed
+		{Date: NewDate(2024, 2, 16), Hours: officeHours("10:00", "11:00")},
+	}
+	slots, err = GenerateSlots(opts, NewDate(2024, 2, 14), NewDate(2024, 2, 17))
+	require.Nil(t, err)
+	require.Equal(t, []string{
+		"2024-02-15 08:30-09:00",
+		"2024-02-15 09:00-09:30",
+		"2024-02-16 10:00-10:30",
+		"2024-02-16 10:30-11:00",
+	}, slotStrings(slots, stockholm))
+
+	// Slots starting before now are skipped
+	opts.Exceptions = nil
+	opts.TimeProvider = NewFakeTimeProvider(time.Date(2024, 2, 14, 9, 10, 0, 0, stockholm))
+	slots, err = GenerateSlots(opts, NewDate(2024, 2, 14), NewDate(2024, 2, 15))
+	require.Nil(t, err)
+	require.Equal(t, []string{
+		"2024-02-14 09:30-10:00",
+		"2024-02-14 13:00-13:30",
+		"2024-02-14 13:30-14:00",
+	}, slotStrings(slots, stockholm))
+
+	// Stop early
+	count := 0
+	require.Nil(t, EachSlot(opts, NewDate(2024, 2, 14), NewDate(2024, 2, 15), func(Interval) bool {
+		count++
+		return false
+	}))
+	require.Equal(t, 1, count)
+}
+
+func Test_GenerateSlots_DST(t *testing.T) {
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	require.Nil(t, err)
+
+	opts := SlotOptions{
+		Location:   stockholm,
+		Schedule:   WeeklySchedule{time.Sunday: officeHours("01:00", "04:00", "08:00", "09:00")},
+		SlotLength: time.Hour,
+	}
+
+	// Spring forward, 02:00-03:00 does not exist on 2024-03-31
+	slots, err := GenerateSlots(opts, NewDate(2024, 3, 31), NewDate(2024, 4, 1))
+	require.Nil(t, err)
+	require.Equal(t, []string{
+		"2024-03-31 01:00-03:00", // an hour long, but the wall clock jumps
+		"2024-03-31 03:00-04:00",
+		"2024-03-31 08:00-09:00",
+	}, slotStrings(slots, stockholm))
+
+	// Fall back, 02:00-03:00 happens twice on 2024-10-27 but is one slot
+	slots, err = GenerateSlots(opts, NewDate(2024, 10, 27), NewDate(2024, 10, 28))
+	require.Nil(t, err)
+	require.Equal(t, []string{
+		"2024-10-27 01:00-02:00",
+		"2024-10-27 02:00-03:00",
+		"2024-10-27 03:00-04:00",
+		"2024-10-27 08:00-09:00",
+	}, slotStrings(slots, stockholm))
+
+	// The morning slots are at the same local time, whatever the offset
+	require.Equal(t, time.Date(2024, 10, 27, 7, 0, 0, 0, time.UTC), slots[len(slots)-1].Start.UTC())
+}
+
+func Test_GenerateSlots_Errors(t *testing.T) {
+	_, err := GenerateSlots(SlotOptions{}, NewDate(2024, 2, 14), NewDate(2024, 2, 15))
+	require.ErrorIs(t, err, ErrInvalidValue)
+
+	_, err = GenerateSlots(SlotOptions{
+		SlotLength: time.Hour,
+		Schedule:   WeeklySchedule{time.Wednesday: officeHours("10:00", "09:00")},
+	}, NewDate(2024, 2, 14), NewDate(2024, 2, 15))
+	require.ErrorIs(t, err, ErrInvalidValue)
+	// Overlapping opening hours would give overlapping slots, touching ones
+	// are fine
+	_, err = GenerateSlots(SlotOptions{
+		SlotLength: time.Hour,
+		Schedule:   WeeklySchedule{time.Wednesday: officeHours("08:00", "12:00", "13:00", "17:00", "11:00", "14:00")},
+	}, NewDate(2024, 2, 14), NewDate(2024, 2, 15))
+	require.ErrorIs(t, err, ErrInvalidValue)
+
+	slots, err := GenerateSlots(SlotOptions{
+		SlotLength: time.Hour,
+		Schedule:   WeeklySchedule{time.Wednesday: officeHours("08:00", "10:00", "10:00", "11:00")},
+	}, NewDate(2024, 2, 14), NewDate(2024, 2, 15))
+	require.Nil(t, err)
+	require.Equal(t, []string{
+		"2024-02-14 08:00-09:00",
+		"2024-02-14 09:00-10:00",
+		"2024-02-14 10:00-11:00",
+	}, slotStrings(slots, time.UTC))
+}
+
+func Test_Interval(t *testing.T) {
+	_require := require.New(t)
+
+	start := time.Date(2024, 2, 14, 9, 0, 0, 0, time.UTC)
+	i := NewInterval(start, start.Add(time.Hour))
+
+	_require.Equal(time.Hour, i.Duration())
+	_require.True(i.Contains(start))
+	_require.False(i.Contains(start.Add(time.Hour)))
+	_require.True(i.Overlaps(NewInterval(start.Add(59*time.Minute), start.Add(2*time.Hour))))
+	_require.False(i.Overlaps(NewInterval(start.Add(time.Hour), start.Add(2*time.Hour))))
+	_require.True(NewInterval(start, start).IsEmpty())
+	_require.Equal(time.Duration(0), NewInterval(start, start.Add(-time.Hour)).Duration())
+	_require.Equal("2024-02-14T09:00:00Z/2024-02-14T10:00:00Z", i.String())
+}
+
+func BenchmarkGenerateSlots_Year(b *testing.B) {
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	hours := officeHours("08:00", "12:00", "13:00", "17:00")
+	opts := SlotOptions{
+		Location: stockholm,
+		Schedule: WeeklySchedule{
+			time.Monday: hours, time.Tuesday: hours, time.Wednesday: hours, time.Thursday: hours, time.Friday: hours,
+		},
+		SlotLength: 30 * time.Minute,
+		Buffer:     5 * time.Minute,
+		Alignment:  15 * time.Minute,
+	}
+
+	// Three appointments a day
+	from := NewDate(2024, 1, 1)
+	for date := from; date.Year == 2024; date = date.AddDays(1) {
+		for _, hour := range []int{9, 11, 14} {
+			start := time.Date(date.Year, date.Month, date.Day, hour, 0, 0, 0, stockholm)
+			opts.Busy = append(opts.Busy, NewInterval(start, start.Add(45*time.Minute)))
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := GenerateSlots(opts, from, NewDate(2025, 1, 1)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
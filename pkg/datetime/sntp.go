@@ -0,0 +1,302 @@
+package datetime
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrSNTP is wrapped by the errors of the SNTP queries of SNTPTimeProvider.
+var ErrSNTP = errors.New("SNTP query failed")
+
+// SNTPOptions configures an SNTPTimeProvider.
+type SNTPOptions struct {
+	// Server is the host and port of the SNTP server, such as "pool.ntp.org:123",
+	// port 123 if no port
+	Server string
+	// Interval between the queries of Start, 1 minute if 0
+	Interval time.Duration
+	// Timeout of a query, 5 seconds if 0
+	Timeout time.Duration
+	// MaxStaleness is how long after the last successful query the offset is
+	// stale, and the provider unhealthy, 4 intervals if 0
+	MaxStaleness time.Duration
+	// TimeProvider is the local clock that is corrected, NewUTCTimeProvider
+	// if nil
+	TimeProvider TimeProvider
+}
+
+// SNTPSample is the result of a successful SNTP query.
+type SNTPSample struct {
+	// Offset of the server clock from the local clock, positive if the local
+	// clock is behind
+	Offset time.Duration
+	// Delay is the round-trip delay of the query, without the processing
+	// time of the server
+	Delay time.Duration
+	// At is the local time of the query
+	At time.Time
+}
+
+// SNTPStatus is the state of an SNTPTimeProvider.
+type SNTPStatus struct {
+	// Offset is applied to the local clock by Now
+	Offset time.Duration
+	// Delay is the round-trip delay of the sample of Offset
+	Delay time.Duration
+	// LastSync is the local time of the last successful query, zero if none
+	LastSync time.Time
+	// LastError is the error of the last query, nil if it succeeded
+	LastError error
+	// Stale is true if there was no successful query within MaxStaleness
+	Stale bool
+}
+
+// Healthy returns true if the offset is up to date.
+func (s SNTPStatus) Healthy() bool {
+	return !s.LastSync.IsZero() && !s.Stale
+}
+
+// The samples kept, the offset of the one with the least delay is applied,
+// like the clock filter of NTP
+const sntpSamples = 8
+
+// SNTPTimeProvider is a TimeProvider correcting a drifting local clock by the
+// offset measured with SNTP, RFC 4330. Until the first successful query, Now
+// returns the local time. It is safe for concurrent use.
+//
+//	tp := datetime.NewSNTPTimeProvider(datetime.SNTPOptions{Server: "ntp.example.com"})
+//	tp.Start()
+//	defer tp.Stop()
+//	...
+//	if status := tp.Status(); !status.Healthy() {
+//		log.Printf("clock not synchronized: %v", status.LastError)
+//	}
+type SNTPTimeProvider struct {
+	opts SNTPOptions
+
+	mu        sync.Mutex
+	samples   []SNTPSample
+	best      SNTPSample
+	lastError error
+	stop      chan struct{}
+	stopped   chan struct{}
+}
+
+// NewSNTPTimeProvider returns a provider querying opts.Server, once Sync or
+// Start is called.
+func NewSNTPTimeProvider(opts SNTPOptions) *SNTPTimeProvider {
+	if _, _, err := net.SplitHostPort(opts.Server); err != nil {
+		opts.Server = net.JoinHostPort(opts.Server, "123")
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.MaxStaleness <= 0 {
+		opts.MaxStaleness = 4 * opts.Interval
+	}
+	if opts.TimeProvider == nil {
+		opts.TimeProvider = NewUTCTimeProvider()
+	}
+	return &SNTPTimeProvider{opts: opts}
+}
+
+// Now returns the local time corrected by the offset.
+func (p *SNTPTimeProvider) Now() time.Time {
+	p.mu.Lock()
+	offset := p.best.Offset
+	p.mu.Unlock()
+	return p.opts.TimeProvider.Now().Add(offset)
+}
+
+// Status returns the offset, and the health of the provider.
+func (p *SNTPTimeProvider) Status() SNTPStatus {
+	now := p.opts.TimeProvider.Now()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status := SNTPStatus{Offset: p.best.Offset, Delay: p.best.Delay, LastError: p.lastError}
+	if len(p.samples) > 0 {
+		status.LastSync = p.samples[len(p.samples)-1].At
+	}
+	status.Stale = status.LastSync.IsZero() || now.Sub(status.LastSync) > p.opts.MaxStaleness
+	return status
+}
+
+// Sync queries the server once, and applies the offset if it has the least
+// delay of the last samples.
+func (p *SNTPTimeProvider) Sync(ctx context.Context) (SNTPSample, error) {
+	sample, err := p.query(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.lastError = err
+	if err != nil {
+		return SNTPSample{}, err
+	}
+	p.samples = append(p.samples, sample)
+	if len(p.samples) > sntpSamples {
+		p.samples = p.samples[1:]
+	}
+	best := append([]SNTPSample(nil), p.samples...)
+	sort.SliceStable(best, func(i, j int) bool { return best[i].Delay < best[j].Delay })
+	p.best = best[0]
+	return sample, nil
+}
+
+// Start queries the server now and then every Interval in the background,
+// until Stop. The errors are reported by Status.
+func (p *SNTPTimeProvider) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stop != nil {
+		return
+	}
+	p.stop, p.stopped = make(chan struct{}), make(chan struct{})
+	go p.run(p.stop, p.stopped)
+}
+
+// Stop stops the queries of Start, and waits for a running query.
+func (p *SNTPTimeProvider) Stop() {
+	p.mu.Lock()
+	stop, stopped := p.stop, p.stopped
+	p.stop, p.stopped = nil, nil
+	p.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-stopped
+	}
+}
+
+func (p *SNTPTimeProvider) run(stop, stopped chan struct{}) {
+	defer close(stopped)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-stopped:
+		}
+	}()
+
+	ticker := time.NewTicker(p.opts.Interval)
+	defer ticker.Stop()
+	for {
+		_, _ = p.Sync(ctx)
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// The seconds from the NTP epoch, 1900, to the Unix epoch
+const ntpEpochOffset = 2208988800
+
+// query sends an SNTP request and returns the offset and delay of the reply.
+func (p *SNTPTimeProvider) query(ctx context.Context) (SNTPSample, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.opts.Timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", p.opts.Server)
+	if err != nil {
+		return SNTPSample{}, fmt.Errorf("%w: %v", ErrSNTP, err)
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+	go func() {
+		// Unblocks the read when ctx is cancelled before the deadline
+		<-ctx.Done()
+		_ = conn.SetDeadline(time.Unix(1, 0))
+	}()
+
+	// LI 0, version 4, mode 3 (client), and the transmit time, which the
+	// server returns as the origin time
+	request := make([]byte, 48)
+	request[0] = 0<<6 | 4<<3 | 3
+	t1 := stopwatchNow(p.opts.TimeProvider)
+	binary.BigEndian.PutUint64(request[40:], timeToNTP(t1))
+	if _, err := conn.Write(request); err != nil {
+		return SNTPSample{}, fmt.Errorf("%w: %v", ErrSNTP, err)
+	}
+
+	reply := make([]byte, 128)
+	for {
+		n, err := conn.Read(reply)
+		if err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			return SNTPSample{}, fmt.Errorf("%w: %v", ErrSNTP, err)
+		}
+		t4 := stopwatchNow(p.opts.TimeProvider)
+
+		// Ignores replies to other requests
+		if n < 48 || binary.BigEndian.Uint64(reply[24:]) != binary.BigEndian.Uint64(request[40:]) {
+			continue
+		}
+		if mode := reply[0] & 0x7; mode != 4 {
+			return SNTPSample{}, fmt.Errorf("%w: unexpected mode %d", ErrSNTP, mode)
+		}
+		if stratum := reply[1]; stratum == 0 || stratum > 15 {
+			return SNTPSample{}, fmt.Errorf("%w: server unsynchronized, stratum %d, code %q", ErrSNTP, stratum, reply[12:16])
+		}
+		if leap := reply[0] >> 6; leap == 3 {
+			return SNTPSample{}, fmt.Errorf("%w: server unsynchronized, leap indicator 3", ErrSNTP)
+		}
+
+		// The offset and delay of RFC 4330, with t1 and t4 of the local
+		// clock, and t2 and t3 of the server
+		t2 := ntpToTime(binary.BigEndian.Uint64(reply[32:]))
+		t3 := ntpToTime(binary.BigEndian.Uint64(reply[40:]))
+		if t3.IsZero() || t3.Before(t2) {
+			return SNTPSample{}, fmt.Errorf("%w: invalid server timestamps", ErrSNTP)
+		}
+		offset := (t2.Sub(t1) + t3.Sub(t4)) / 2
+		delay := t4.Sub(t1) - t3.Sub(t2)
+		if delay < 0 {
+			delay = 0
+		}
+		return SNTPSample{Offset: offset, Delay: delay, At: t4.Round(0)}, nil
+	}
+}
+
+// timeToNTP returns the NTP timestamp of t, 32 bits of seconds since 1900,
+// wrapping in 2036, and 32 bits of fraction.
+func timeToNTP(t time.Time) uint64 {
+	seconds := uint64(t.Unix() + ntpEpochOffset)
+	fraction := uint64(t.Nanosecond()) << 32 / uint64(time.Second)
+	return seconds<<32 | fraction
+}
+
+// ntpToTime returns the time of an NTP timestamp, in 1968 to 2104 as in RFC
+// 4330, or the zero time for the zero timestamp.
+func ntpToTime(ntp uint64) time.Time {
+	if ntp == 0 {
+		return time.Time{}
+	}
+	seconds := int64(ntp >> 32)
+	if seconds < 1<<31 {
+		// The era after 2036
+		seconds += 1 << 32
+	}
+	nanos := int64((ntp & 0xffffffff) * uint64(time.Second) >> 32)
+	return time.Unix(seconds-ntpEpochOffset, nanos).UTC()
+}
@@ -0,0 +1,195 @@
+package datetime
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// sntpServer is an in-process SNTP server on the loopback interface.
+type sntpServer struct {
+	conn  net.PacketConn
+	clock TimeProvider
+
+	mu sync.Mutex
+	// stratum of the replies, 0 for a kiss-o'-death
+	stratum byte
+	// inFlight is called between receiving a request and reading the clock,
+	// such as to advance a fake clock by a network delay
+	inFlight func()
+	// drop drops the requests
+	drop bool
+}
+
+func startSNTPServer(t *testing.T, clock TimeProvider) *sntpServer {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.Nil(t, err)
+	s := &sntpServer{conn: conn, clock: clock, stratum: 1}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 128)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			stratum, inFlight, drop := s.stratum, s.inFlight, s.drop
+			s.mu.Unlock()
+			if n < 48 || drop {
+				continue
+			}
+			if inFlight != nil {
+				inFlight()
+			}
+
+			reply := make([]byte, 48)
+			reply[0] = 0<<6 | 4<<3 | 4
+			reply[1] = stratum
+			if stratum == 0 {
+				copy(reply[12:], "RATE")
+			} else {
+				copy(reply[12:], "GPS\x00")
+			}
+			copy(reply[24:32], buf[40:48])
+			binary.BigEndian.PutUint64(reply[32:], timeToNTP(clock.Now()))
+			binary.BigEndian.PutUint64(reply[40:], timeToNTP(clock.Now()))
+			_, _ = conn.WriteTo(reply, addr)
+		}
+	}()
+	return s
+}
+
+func (s *sntpServer) set(f func(s *sntpServer)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(s)
+}
+
+func Test_SNTPTimeProvider(t *testing.T) {
+	_require := require.New(t)
+
+	// The local clock is 3 seconds behind the server
+	local := NewManualTimeProvider(decoratorsNow)
+	skew := 3 * time.Second
+	server := startSNTPServer(t, Offset(local, skew))
+
+	tp := NewSNTPTimeProvider(SNTPOptions{Server: server.conn.LocalAddr().String(), TimeProvider: local})
+	_require.False(tp.Status().Healthy())
+	_require.WithinDuration(decoratorsNow, tp.Now(), time.Millisecond)
+
+	sample, err := tp.Sync(context.Background())
+	_require.Nil(err)
+	_require.InDelta(float64(skew), float64(sample.Offset), 2)
+	_require.InDelta(0, float64(sample.Delay), 2)
+
+	status := tp.Status()
+	_require.True(status.Healthy())
+	_require.Nil(status.LastError)
+	_require.Equal(sample.Offset, status.Offset)
+	_require.Equal(sample.At, status.LastSync)
+	_require.WithinDuration(local.Now().Add(skew), tp.Now(), 2)
+
+	// A sample with a network delay to the server, making the offset less
+	// accurate, isn't applied
+	server.set(func(s *sntpServer) { s.inFlight = func() { local.Advance(100 * time.Millisecond) } })
+	sample, err = tp.Sync(context.Background())
+	_require.Nil(err)
+	_require.InDelta(float64(skew+50*time.Millisecond), float64(sample.Offset), 2)
+	_require.InDelta(float64(skew), float64(tp.Status().Offset), 2)
+
+	// Stale, but the last offset is still applied
+	local.Advance(3 * time.Minute)
+	_require.True(tp.Status().Healthy())
+	local.Advance(2 * time.Minute)
+	_require.False(tp.Status().Healthy())
+	_require.True(tp.Status().Stale)
+	_require.WithinDuration(local.Now().Add(skew), tp.Now(), 2)
+
+	// The delayed samples are dropped after 8 samples
+	for i := 0; i < 8; i++ {
+		_, err = tp.Sync(context.Background())
+		_require.Nil(err)
+	}
+	_require.True(tp.Status().Healthy())
+	_require.InDelta(float64(skew+50*time.Millisecond), float64(tp.Status().Offset), 2)
+}
+
+func Test_SNTPTimeProvider_Errors(t *testing.T) {
+	_require := require.New(t)
+
+	local := NewManualTimeProvider(decoratorsNow)
+	server := startSNTPServer(t, Offset(local, time.Second))
+	tp := NewSNTPTimeProvider(SNTPOptions{
+		Server:       server.conn.LocalAddr().String(),
+		Timeout:      50 * time.Millisecond,
+		TimeProvider: local,
+	})
+	_, err := tp.Sync(context.Background())
+	_require.Nil(err)
+
+	// Kiss-o'-death
+	server.set(func(s *sntpServer) { s.stratum = 0 })
+	_, err = tp.Sync(context.Background())
+	_require.ErrorIs(err, ErrSNTP)
+	_require.Contains(err.Error(), `"RATE"`)
+
+	status := tp.Status()
+	_require.ErrorIs(status.LastError, ErrSNTP)
+	_require.True(status.Healthy())
+	_require.InDelta(float64(time.Second), float64(status.Offset), 2)
+
+	// No reply
+	server.set(func(s *sntpServer) { s.stratum, s.drop = 1, true })
+	_, err = tp.Sync(context.Background())
+	_require.ErrorIs(err, ErrSNTP)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = tp.Sync(ctx)
+	_require.ErrorIs(err, ErrSNTP)
+
+	_require.Equal("ntp.example.com:123", NewSNTPTimeProvider(SNTPOptions{Server: "ntp.example.com"}).opts.Server)
+}
+
+func Test_SNTPTimeProvider_Start(t *testing.T) {
+	_require := require.New(t)
+
+	server := startSNTPServer(t, Offset(NewUTCTimeProvider(), time.Hour))
+	tp := NewSNTPTimeProvider(SNTPOptions{Server: server.conn.LocalAddr().String(), Interval: 10 * time.Millisecond})
+	tp.Start()
+	tp.Start()
+	defer tp.Stop()
+
+	_require.Eventually(func() bool { return tp.Status().Healthy() }, 5*time.Second, time.Millisecond)
+	_require.WithinDuration(time.Now().Add(time.Hour), tp.Now(), 100*time.Millisecond)
+
+	// Keeps querying
+	lastSync := tp.Status().LastSync
+	_require.Eventually(func() bool { return tp.Status().LastSync.After(lastSync) }, 5*time.Second, time.Millisecond)
+
+	tp.Stop()
+	tp.Stop()
+}
+
+func Test_NTPTimestamps(t *testing.T) {
+	_require := require.New(t)
+
+	for _, tm := range []time.Time{
+		decoratorsNow,
+		time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2036, 2, 7, 6, 28, 15, 0, time.UTC),
+		time.Date(2036, 2, 7, 6, 28, 17, 0, time.UTC), // era 1, its first second is the zero timestamp
+		time.Date(2100, 1, 1, 0, 0, 0, 999999999, time.UTC),
+	} {
+		_require.WithinDuration(tm, ntpToTime(timeToNTP(tm)), time.Nanosecond, tm)
+	}
+	_require.Equal(uint64(ntpEpochOffset)<<32, timeToNTP(time.Unix(0, 0)))
+	_require.True(ntpToTime(0).IsZero())
+}
@@ -0,0 +1,216 @@
+package datetime
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Layouts of the text representations returned by Postgres drivers for
+// "timestamptz" and "timestamp" columns, such as "2024-02-14 09:00:00.5+01".
+var sqlTimestampLayouts = []string{
+	"2006-01-02 15:04:05.999999999Z07:00:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+}
+
+// Scan implements the sql.Scanner interface for "date" columns.
+func (d *Date) Scan(src any) error {
+	switch v := src.(type) {
+	case time.Time:
+		*d = DateOf(v)
+		return nil
+	case string:
+		return d.scanString(v)
+	case []byte:
+		return d.scanString(string(v))
+	case nil:
+		return fmt.Errorf("%w: cannot scan NULL into Date, use NullDate", ErrInvalidValue)
+	default:
+		return fmt.Errorf("%w: cannot scan %T into Date", ErrInvalidValue, src)
+	}
+}
+
+func (d *Date) scanString(s string) error {
+	// Drivers using the text protocol may return a date as a timestamp, whose
+	// date is the one written, in its own offset
+	if len(s) > len(ISO8601Date) {
+		t, err := parseSQLTimestamp(s, time.UTC)
+		if err != nil {
+			return err
+		}
+		*d = DateOf(t)
+		return nil
+	}
+	date, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+	*d = date
+	return nil
+}
+
+// Value implements the driver.Valuer interface, a Date is sent as an ISO8601
+// date string. An invalid Date, such as the zero value, is an error.
+func (d Date) Value() (driver.Value, error) {
+	if !d.IsValid() {
+		return nil, fmt.Errorf("%w: cannot send the invalid date %04d-%02d-%02d", ErrInvalidValue, d.Year, int(d.Month), d.Day)
+	}
+	return d.String(), nil
+}
+
+// Scan implements the sql.Scanner interface for "time" columns.
+func (t *TimeOfDay) Scan(src any) error {
+	switch v := src.(type) {
+	case time.Time:
+		*t = TimeOfDayOf(v)
+		return nil
+	case string:
+		return t.scanString(v)
+	case []byte:
+		return t.scanString(string(v))
+	case nil:
+		return fmt.Errorf("%w: cannot scan NULL into TimeOfDay, use NullTimeOfDay", ErrInvalidValue)
+	default:
+		return fmt.Errorf("%w: cannot scan %T into TimeOfDay", ErrInvalidValue, src)
+	}
+}
+
+func (t *TimeOfDay) scanString(s string) error {
+	timeOfDay, err := ParseTimeOfDay(s)
+	if err != nil {
+		return err
+	}
+	*t = timeOfDay
+	return nil
+}
+
+// Value implements the driver.Valuer interface, a TimeOfDay is sent as a
+// "15:04:05.999999999" string.
+func (t TimeOfDay) Value() (driver.Value, error) {
+	return t.String(), nil
+}
+
+// Scan implements the sql.Scanner interface for "timestamptz" columns.
+//
+// The scanned instant is presented in the location z already has, which makes
+// it possible to scan into a ZonedDateTime prepared with the clinic's
+// location. A z without a location keeps the location reported by the driver.
+func (z *ZonedDateTime) Scan(src any) error {
+	var t time.Time
+	switch v := src.(type) {
+	case time.Time:
+		t = v
+	case string, []byte:
+		var err error
+		if t, err = parseSQLTimestamp(fmt.Sprintf("%s", v), z.Location()); err != nil {
+			return err
+		}
+	case nil:
+		return fmt.Errorf("%w: cannot scan NULL into ZonedDateTime, use NullZonedDateTime", ErrInvalidValue)
+	default:
+		return fmt.Errorf("%w: cannot scan %T into ZonedDateTime", ErrInvalidValue, src)
+	}
+
+	if z.loc != nil {
+		z.t = t.In(z.loc)
+		return nil
+	}
+	*z = NewZonedDateTime(t, t.Location())
+	return nil
+}
+
+// Value implements the driver.Valuer interface, a ZonedDateTime is sent as
+// the Time of the instant.
+func (z ZonedDateTime) Value() (driver.Value, error) {
+	return z.t, nil
+}
+
+// parseSQLTimestamp parses the text form of a timestamp, a timestamp without
+// an offset is interpreted in the location l.
+func parseSQLTimestamp(s string, l *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range sqlTimestampLayouts {
+		if t, err := time.ParseInLocation(layout, s, l); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q is not a timestamp", ErrInvalidValue, s)
+}
+
+// NullDate represents a Date that may be NULL.
+type NullDate struct {
+	Date  Date
+	Valid bool // Valid is true if Date is not NULL
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *NullDate) Scan(src any) error {
+	if src == nil {
+		n.Date, n.Valid = Date{}, false
+		return nil
+	}
+	n.Valid = true
+	return n.Date.Scan(src)
+}
+
+// Value implements the driver.Valuer interface.
+func (n NullDate) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Date.Value()
+}
+
+// NullTimeOfDay represents a TimeOfDay that may be NULL.
+type NullTimeOfDay struct {
+	TimeOfDay TimeOfDay
+	Valid     bool // Valid is true if TimeOfDay is not NULL
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *NullTimeOfDay) Scan(src any) error {
+	if src == nil {
+		n.TimeOfDay, n.Valid = TimeOfDay{}, false
+		return nil
+	}
+	n.Valid = true
+	return n.TimeOfDay.Scan(src)
+}
+
+// Value implements the driver.Valuer interface.
+func (n NullTimeOfDay) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.TimeOfDay.Value()
+}
+
+// NullZonedDateTime represents a ZonedDateTime that may be NULL.
+type NullZonedDateTime struct {
+	ZonedDateTime ZonedDateTime
+	Valid         bool // Valid is true if ZonedDateTime is not NULL
+}
+
+// Scan implements the sql.Scanner interface. Like ZonedDateTime.Scan, the
+// location already set on ZonedDateTime is kept, also for NULL.
+func (n *NullZonedDateTime) Scan(src any) error {
+	if src == nil {
+		n.ZonedDateTime.t, n.Valid = time.Time{}.In(n.ZonedDateTime.Location()), false
+		return nil
+	}
+	n.Valid = true
+	return n.ZonedDateTime.Scan(src)
+}
+
+// Value implements the driver.Valuer interface.
+func (n NullZonedDateTime) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.ZonedDateTime.Value()
+}
@@ -0,0 +1,235 @@
+package datetime
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConnector is a database/sql driver that returns a single row with the
+// configured values for every query and records the arguments of every exec.
+type fakeConnector struct {
+	row  []driver.Value
+	args []driver.Value
+}
+
+func (c *fakeConnector) Connect(context.Context) (driver.Conn, error) { return &fakeConn{c}, nil }
+func (c *fakeConnector) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ c *fakeConnector }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{c.c}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                 { return nil, driver.ErrSkip }
+
+type fakeStmt struct{ c *fakeConnector }
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.c.args = args
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &fakeRows{row: s.c.row}, nil
+}
+
+type fakeRows struct {
+	row  []driver.Value
+	done bool
+}
+
+func (r *fakeRows) Columns() []string {
+	columns := make([]string, len(r.row))
+	for i := range columns {
+		columns[i] = "c"
+	}
+	return columns
+}
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.row)
+	return nil
+}
+
+func openFakeDB(row ...driver.Value) (*sql.DB, *fakeConnector) {
+	connector := &fakeConnector{row: row}
+	return sql.OpenDB(connector), connector
+}
+
+func Test_SQL_Scan(t *testing.T) {
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	require.Nil(t, err)
+
+	instant := time.Date(2024, 2, 14, 8, 0, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		name                  string
+		date, timeOfDay, zdt  driver.Value
+		expectedTimeOfDay     TimeOfDay
+		expectedZonedDateTime time.Time
+	}{
+		{
+			"pgx",
+			time.Date(2024, 2, 14, 0, 0, 0, 0, time.UTC),
+			"09:00:00",
+			instant.In(time.Local),
+			NewTimeOfDay(9, 0, 0),
+			instant,
+		},
+		{
+			"lib/pq",
+			time.Date(2024, 2, 14, 0, 0, 0, 0, time.FixedZone("", 0)),
+			[]byte("09:00:00.25"),
+			instant.In(time.FixedZone("", 3600)),
+			TimeOfDay{Hour: 9, Nanosecond: int(250 * time.Millisecond)},
+			instant,
+		},
+		{
+			"text protocol",
+			[]byte("2024-02-14"),
+			[]byte("09:00"),
+			[]byte("2024-02-14 09:00:00+01"),
+			NewTimeOfDay(9, 0, 0),
+			instant,
+		},
+		{
+			"text protocol with fraction and minutes offset",
+			"2024-02-14",
+			"09:00:00",
+			"2024-02-14 13:30:00.5+05:30",
+			NewTimeOfDay(9, 0, 0),
+			instant.Add(500 * time.Millisecond),
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			db, _ := openFakeDB(test.date, test.timeOfDay, test.zdt)
+			defer db.Close()
+
+			var date Date
+			var timeOfDay TimeOfDay
+			zdt := NewZonedDateTime(time.Time{}, stockholm)
+			err := db.QueryRow("SELECT").Scan(&date, &timeOfDay, &zdt)
+			require.Nil(t, err)
+
+			require.Equal(t, NewDate(2024, time.February, 14), date)
+			require.Equal(t, test.expectedTimeOfDay, timeOfDay)
+			require.True(t, test.expectedZonedDateTime.Equal(zdt.Time()))
+			require.Equal(t, stockholm, zdt.Location())
+		})
+	}
+
+	t.Run("ZonedDateTime without location keeps driver location", func(t *testing.T) {
+		db, _ := openFakeDB(instant.In(stockholm))
+		defer db.Close()
+
+		var zdt ZonedDateTime
+		require.Nil(t, db.QueryRow("SELECT").Scan(&zdt))
+		require.Equal(t, "2024-02-14T09:00:00+01:00[Europe/Stockholm]", zdt.String())
+	})
+
+	t.Run("Date from a timestamp", func(t *testing.T) {
+		for _, value := range []driver.Value{"2024-02-14 09:00:00+01", []byte("2024-02-14T09:00:00Z")} {
+			db, _ := openFakeDB(value)
+
+			var date Date
+			require.Nil(t, db.QueryRow("SELECT").Scan(&date))
+			require.Equal(t, NewDate(2024, time.February, 14), date)
+			db.Close()
+		}
+
+		for _, value := range []driver.Value{"2024-02-14garbage", "2024-02-14 garbage", "0044-03-15 BC"} {
+			db, _ := openFakeDB(value)
+
+			var date Date
+			require.ErrorIs(t, db.QueryRow("SELECT").Scan(&date), ErrInvalidValue, value)
+			db.Close()
+		}
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		for _, value := range []driver.Value{nil, "2024-13-01", int64(1)} {
+			db, _ := openFakeDB(value)
+
+			var date Date
+			var timeOfDay TimeOfDay
+			var zdt ZonedDateTime
+			require.ErrorIs(t, db.QueryRow("SELECT").Scan(&date), ErrInvalidValue)
+			require.ErrorIs(t, db.QueryRow("SELECT").Scan(&timeOfDay), ErrInvalidValue)
+			require.ErrorIs(t, db.QueryRow("SELECT").Scan(&zdt), ErrInvalidValue)
+			db.Close()
+		}
+	})
+}
+
+func Test_SQL_ScanNull(t *testing.T) {
+	db, _ := openFakeDB(nil, nil, nil)
+	defer db.Close()
+
+	nullDate := NullDate{Date: NewDate(2024, 2, 14), Valid: true}
+	nullTimeOfDay := NullTimeOfDay{TimeOfDay: NewTimeOfDay(9, 0, 0), Valid: true}
+	nullZonedDateTime := NullZonedDateTime{ZonedDateTime: NewZonedDateTime(time.Date(2024, 2, 14, 8, 0, 0, 0, time.UTC), time.UTC), Valid: true}
+	err := db.QueryRow("SELECT").Scan(&nullDate, &nullTimeOfDay, &nullZonedDateTime)
+	require.Nil(t, err)
+	require.False(t, nullDate.Valid)
+	require.False(t, nullTimeOfDay.Valid)
+	require.False(t, nullZonedDateTime.Valid)
+	require.True(t, nullZonedDateTime.ZonedDateTime.Time().IsZero())
+	require.Equal(t, time.UTC, nullZonedDateTime.ZonedDateTime.Location())
+
+	db, _ = openFakeDB("2024-02-14", "09:00:00", "2024-02-14 08:00:00Z")
+	defer db.Close()
+
+	err = db.QueryRow("SELECT").Scan(&nullDate, &nullTimeOfDay, &nullZonedDateTime)
+	require.Nil(t, err)
+	require.Equal(t, NullDate{Date: NewDate(2024, 2, 14), Valid: true}, nullDate)
+	require.Equal(t, NullTimeOfDay{TimeOfDay: NewTimeOfDay(9, 0, 0), Valid: true}, nullTimeOfDay)
+	require.True(t, nullZonedDateTime.Valid)
+	require.Equal(t, "2024-02-14T08:00:00Z[UTC]", nullZonedDateTime.ZonedDateTime.String())
+}
+
+func Test_SQL_Value(t *testing.T) {
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	require.Nil(t, err)
+
+	db, connector := openFakeDB()
+	defer db.Close()
+
+	instant := time.Date(2024, 2, 14, 8, 0, 0, 0, time.UTC)
+
+	_, err = db.Exec("INSERT",
+		NewDate(2024, 2, 14),
+		TimeOfDay{Hour: 9, Minute: 30, Nanosecond: 1000},
+		NewZonedDateTime(instant, stockholm),
+		NullDate{},
+		NullTimeOfDay{TimeOfDay: NewTimeOfDay(9, 0, 0), Valid: true},
+		NullZonedDateTime{},
+	)
+	require.Nil(t, err)
+	require.Equal(t, []driver.Value{
+		"2024-02-14",
+		"09:30:00.000001",
+		instant.In(stockholm),
+		nil,
+		"09:00:00",
+		nil,
+	}, connector.args)
+
+	_, err = db.Exec("INSERT", Date{})
+	require.ErrorIs(t, err, ErrInvalidValue)
+	_, err = db.Exec("INSERT", NewDate(2023, 2, 29))
+	require.ErrorIs(t, err, ErrInvalidValue)
+}
@@ -0,0 +1,142 @@
+package datetime
+
+import (
+	"sync"
+	"time"
+
+	durpb "google.golang.org/protobuf/types/known/durationpb"
+)
+
+// monotonicTimeProvider is implemented by the TimeProviders of the real
+// clock, to measure elapsed time with the monotonic clock. Their Now can't be
+// used, since Time.UTC strips the monotonic clock reading, so that the wall
+// clock, which may be set back or forward, would be measured instead.
+type monotonicTimeProvider interface {
+	monotonicNow() time.Time
+}
+
+func (*utcTimeProvider) monotonicNow() time.Time {
+	return time.Now()
+}
+
+// Offset and InLocation don't change elapsed time, the other decorators do
+func (o *offsetTimeProvider) monotonicNow() time.Time {
+	return stopwatchNow(o.tp)
+}
+
+func (l *locationTimeProvider) monotonicNow() time.Time {
+	return stopwatchNow(l.tp)
+}
+
+// The offset of SNTPTimeProvider jumps when it is synced, so elapsed time is
+// measured with its local clock
+func (p *SNTPTimeProvider) monotonicNow() time.Time {
+	return stopwatchNow(p.opts.TimeProvider)
+}
+
+// stopwatchNow returns the current time of tp to measure elapsed time, with a
+// monotonic clock reading for the real clock.
+func stopwatchNow(tp TimeProvider) time.Time {
+	if m, ok := tp.(monotonicTimeProvider); ok {
+		return m.monotonicNow()
+	}
+	return tp.Now()
+}
+
+// Elapsed returns how long f takes, measured with the monotonic clock for
+// NewUTCTimeProvider and with the time of tp for fakes, such as for latency
+// metrics.
+func Elapsed(tp TimeProvider, f func()) time.Duration {
+	sw := StartStopwatch(tp)
+	f()
+	return sw.Elapsed()
+}
+
+// Stopwatch measures elapsed time from a TimeProvider, with the monotonic
+// clock for NewUTCTimeProvider, so that it isn't affected by changes of the
+// wall clock or of the offset of SNTPTimeProvider, and with the time of tp for
+// fakes, such as the virtual time of a simulation. It is safe for concurrent use.
+//
+//	sw := datetime.StartStopwatch(timeProvider)
+//	fetchPatient()
+//	sw.Lap() // the time of fetchPatient
+//	fetchAppointments()
+//	sw.Lap() // the time of fetchAppointments
+//	latency.Observe(sw.Elapsed().Seconds())
+type Stopwatch struct {
+	tp       TimeProvider
+	mu       sync.Mutex
+	start    time.Time
+	lapStart time.Time
+	laps     []time.Duration
+	splits   []time.Duration
+}
+
+// StartStopwatch returns a Stopwatch started at the current time of tp.
+func StartStopwatch(tp TimeProvider) *Stopwatch {
+	now := stopwatchNow(tp)
+	return &Stopwatch{tp: tp, start: now, lapStart: now}
+}
+
+// Elapsed returns the time elapsed since the start.
+func (s *Stopwatch) Elapsed() time.Duration {
+	now := stopwatchNow(s.tp)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return now.Sub(s.start)
+}
+
+// ElapsedProto returns Elapsed as a google.protobuf.Duration.
+func (s *Stopwatch) ElapsedProto() *durpb.Duration {
+	return DurationToProtoDuration(s.Elapsed())
+}
+
+// Lap ends the current lap and starts the next, and returns the time elapsed
+// since the start of the lap, the start of the stopwatch for the first lap.
+func (s *Stopwatch) Lap() time.Duration {
+	now := stopwatchNow(s.tp)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lap := now.Sub(s.lapStart)
+	s.lapStart = now
+	s.laps = append(s.laps, lap)
+	return lap
+}
+
+// Split records and returns the time elapsed since the start, without ending
+// the current lap.
+func (s *Stopwatch) Split() time.Duration {
+	now := stopwatchNow(s.tp)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	split := now.Sub(s.start)
+	s.splits = append(s.splits, split)
+	return split
+}
+
+// Laps returns the durations of the ended laps, in order.
+func (s *Stopwatch) Laps() []time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]time.Duration(nil), s.laps...)
+}
+
+// Splits returns the recorded splits, in order.
+func (s *Stopwatch) Splits() []time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]time.Duration(nil), s.splits...)
+}
+
+// Restart clears the laps and splits and restarts the stopwatch at the
+// current time.
+func (s *Stopwatch) Restart() {
+	now := stopwatchNow(s.tp)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.start, s.lapStart = now, now
+	s.laps, s.splits = nil, nil
+}
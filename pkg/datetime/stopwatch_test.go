@@ -0,0 +1,80 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	durpb "google.golang.org/protobuf/types/known/durationpb"
+)
+
+func Test_Stopwatch(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewManualTimeProvider(decoratorsNow)
+	sw := StartStopwatch(tp)
+	_require.Equal(time.Duration(0), sw.Elapsed())
+
+	tp.Advance(100 * time.Millisecond)
+	_require.Equal(100*time.Millisecond, sw.Lap())
+	tp.Advance(50 * time.Millisecond)
+	_require.Equal(150*time.Millisecond, sw.Split())
+	tp.Advance(250 * time.Millisecond)
+	_require.Equal(300*time.Millisecond, sw.Lap())
+	tp.Advance(time.Second)
+
+	_require.Equal(1400*time.Millisecond, sw.Elapsed())
+	_require.Equal([]time.Duration{100 * time.Millisecond, 300 * time.Millisecond}, sw.Laps())
+	_require.Equal([]time.Duration{150 * time.Millisecond}, sw.Splits())
+	_require.Equal(&durpb.Duration{Seconds: 1, Nanos: 400000000}, sw.ElapsedProto())
+
+	sw.Restart()
+	_require.Equal(time.Duration(0), sw.Elapsed())
+	_require.Empty(sw.Laps())
+	_require.Empty(sw.Splits())
+
+	// A fake time going backwards, such as a skewed clock
+	tp.Set(tp.Now().Add(-time.Second))
+	_require.Equal(-time.Second, sw.Elapsed())
+}
+
+func Test_Stopwatch_Monotonic(t *testing.T) {
+	_require := require.New(t)
+
+	for name, tp := range map[string]TimeProvider{
+		"UTC":        NewUTCTimeProvider(),
+		"Offset":     Offset(NewUTCTimeProvider(), -24*time.Hour),
+		"InLocation": InLocation(NewUTCTimeProvider(), time.UTC),
+		"SNTP":       NewSNTPTimeProvider(SNTPOptions{Server: "ntp.example.com"}),
+	} {
+		sw := StartStopwatch(tp)
+		// Round(0) strips the monotonic clock reading, like Now does
+		_require.NotEqual(sw.start.Round(0), sw.start, name)
+		now := tp.Now()
+		_require.Equal(now.Round(0), now, name)
+	}
+
+	elapsed := Elapsed(NewUTCTimeProvider(), func() { time.Sleep(10 * time.Millisecond) })
+	_require.GreaterOrEqual(elapsed, 10*time.Millisecond)
+}
+
+func Test_Stopwatch_Virtual(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewManualTimeProvider(decoratorsNow)
+	elapsed := Elapsed(Scaled(tp, 60, decoratorsNow), func() { tp.Advance(time.Second) })
+	_require.Equal(time.Minute, elapsed)
+
+	elapsed = Elapsed(Frozen(NewUTCTimeProvider()), func() { time.Sleep(time.Millisecond) })
+	_require.Equal(time.Duration(0), elapsed)
+
+	// A sync of the SNTP offset isn't elapsed time
+	sntp := NewSNTPTimeProvider(SNTPOptions{Server: "ntp.example.com", TimeProvider: tp})
+	elapsed = Elapsed(sntp, func() {
+		sntp.mu.Lock()
+		sntp.best.Offset = time.Hour
+		sntp.mu.Unlock()
+		tp.Advance(time.Second)
+	})
+	_require.Equal(time.Second, elapsed)
+}
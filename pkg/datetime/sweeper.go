@@ -0,0 +1,160 @@
+package datetime
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+type sweeperItem[K comparable] struct {
+	key      K
+	deadline time.Time
+	index    int
+}
+
+type sweeperHeap[K comparable] []*sweeperItem[K]
+
+func (h sweeperHeap[K]) Len() int           { return len(h) }
+func (h sweeperHeap[K]) Less(i, j int) bool { return h[i].deadline.Before(h[j].deadline) }
+func (h sweeperHeap[K]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+func (h *sweeperHeap[K]) Push(x any) {
+	item := x.(*sweeperItem[K])
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+func (h *sweeperHeap[K]) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// Sweeper expires the items of a set at their deadlines, in deadline order,
+// such as the booking holds not confirmed in time:
+//
+//	holds := datetime.NewSweeper(timeProvider, func(slotID string, deadline time.Time) {
+//		releaseSlot(slotID)
+//	})
+//	holds.Set(slotID, datetime.DeadlineAfter(timeProvider, 10*time.Minute).At())
+//	go holds.Run(ctx)
+//
+// Like Deadline, an item expires from its deadline on. It is safe for
+// concurrent use, and onExpire may call the Sweeper.
+type Sweeper[K comparable] struct {
+	tp       TimeProvider
+	onExpire func(key K, deadline time.Time)
+	mu       sync.Mutex
+	heap     sweeperHeap[K]
+	items    map[K]*sweeperItem[K]
+	wake     context.CancelFunc
+}
+
+// NewSweeper returns an empty sweeper on the time of tp, calling onExpire for
+// the expired items.
+func NewSweeper[K comparable](tp TimeProvider, onExpire func(key K, deadline time.Time)) *Sweeper[K] {
+	return &Sweeper[K]{tp: tp, onExpire: onExpire, items: make(map[K]*sweeperItem[K])}
+}
+
+// Set adds key with the deadline, or moves the deadline of key.
+func (s *Sweeper[K]) Set(key K, deadline time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if item, ok := s.items[key]; ok {
+		item.deadline = deadline
+		heap.Fix(&s.heap, item.index)
+	} else {
+		item := &sweeperItem[K]{key: key, deadline: deadline}
+		heap.Push(&s.heap, item)
+		s.items[key] = item
+	}
+	if s.wake != nil {
+		s.wake()
+	}
+}
+
+// Remove removes key without expiring it, and returns false if there is no
+// such item.
+func (s *Sweeper[K]) Remove(key K) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[key]
+	if !ok {
+		return false
+	}
+	heap.Remove(&s.heap, item.index)
+	delete(s.items, key)
+	return true
+}
+
+// Deadline returns the deadline of key, and false if there is no such item.
+func (s *Sweeper[K]) Deadline(key K) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[key]
+	if !ok {
+		return time.Time{}, false
+	}
+	return item.deadline, true
+}
+
+// Len returns the number of items not expired yet.
+func (s *Sweeper[K]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.items)
+}
+
+// Sweep expires the items with deadlines until now, in deadline order, and
+// returns the number of expired items.
+func (s *Sweeper[K]) Sweep() int {
+	now := s.tp.Now()
+	expired := 0
+	for {
+		s.mu.Lock()
+		if len(s.heap) == 0 || now.Before(s.heap[0].deadline) {
+			s.mu.Unlock()
+			return expired
+		}
+		item := heap.Pop(&s.heap).(*sweeperItem[K])
+		delete(s.items, item.key)
+		s.mu.Unlock()
+
+		s.onExpire(item.key, item.deadline)
+		expired++
+	}
+}
+
+// Run expires the items at their deadlines until ctx is done, and returns
+// the error of ctx. It waits with SleepUntil, so it can be tested with
+// ManualTimeProvider.
+func (s *Sweeper[K]) Run(ctx context.Context) error {
+	for {
+		s.Sweep()
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		sleepCtx, cancel := context.WithCancel(ctx)
+		s.mu.Lock()
+		var next time.Time
+		if len(s.heap) > 0 {
+			next = s.heap[0].deadline
+		}
+		s.wake = cancel
+		s.mu.Unlock()
+
+		if next.IsZero() {
+			sleepUntilDone(sleepCtx, s.tp)
+		} else {
+			_ = SleepUntil(sleepCtx, s.tp, next)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,129 @@
+package datetime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type sweeperExpiry struct {
+	key      string
+	deadline time.Time
+}
+
+func Test_Sweeper(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewManualTimeProvider(decoratorsNow)
+	var expired []sweeperExpiry
+	var sweeper *Sweeper[string]
+	sweeper = NewSweeper(tp, func(key string, deadline time.Time) {
+		expired = append(expired, sweeperExpiry{key, deadline})
+		// The sweeper can be called back
+		if key == "renewing" {
+			sweeper.Set("renewed", deadline.Add(time.Hour))
+		}
+	})
+
+	at := func(minutes int) time.Time {
+		return decoratorsNow.Add(time.Duration(minutes) * time.Minute)
+	}
+	sweeper.Set("c", at(30))
+	sweeper.Set("a", at(10))
+	sweeper.Set("b", at(20))
+	sweeper.Set("moved", at(5))
+	sweeper.Set("moved", at(25))
+	sweeper.Set("removed", at(1))
+	_require.True(sweeper.Remove("removed"))
+	_require.False(sweeper.Remove("removed"))
+	_require.Equal(4, sweeper.Len())
+	deadline, ok := sweeper.Deadline("moved")
+	_require.True(ok)
+	_require.Equal(at(25), deadline)
+	_, ok = sweeper.Deadline("removed")
+	_require.False(ok)
+
+	_require.Equal(0, sweeper.Sweep())
+
+	// Expired from the deadline on, in deadline order
+	tp.Set(at(20))
+	_require.Equal(2, sweeper.Sweep())
+	_require.Equal([]sweeperExpiry{{"a", at(10)}, {"b", at(20)}}, expired)
+
+	sweeper.Set("renewing", at(26))
+	tp.Set(at(40))
+	_require.Equal(3, sweeper.Sweep())
+	_require.Equal([]sweeperExpiry{{"moved", at(25)}, {"renewing", at(26)}, {"c", at(30)}}, expired[2:])
+	_require.Equal(1, sweeper.Len())
+	deadline, _ = sweeper.Deadline("renewed")
+	_require.Equal(at(86), deadline)
+}
+
+func Test_Sweeper_Run(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewManualTimeProvider(decoratorsNow)
+	expired := make(chan string, 100)
+	sweeper := NewSweeper(tp, func(key int, deadline time.Time) {
+		expired <- fmt.Sprint(key, " ", deadline.Sub(decoratorsNow))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- sweeper.Run(ctx) }()
+
+	// Waits for items, then for the earliest
+	sweeper.Set(1, decoratorsNow.Add(time.Hour))
+	_require.Eventually(func() bool { return tp.Sleepers() == 1 }, time.Second, time.Millisecond)
+	sweeper.Set(2, decoratorsNow.Add(time.Minute))
+	_require.Eventually(func() bool { return tp.Sleepers() == 1 }, time.Second, time.Millisecond)
+
+	tp.Advance(time.Minute)
+	_require.Equal("2 1m0s", <-expired)
+	_require.Eventually(func() bool { return tp.Sleepers() == 1 }, time.Second, time.Millisecond)
+	tp.Advance(2 * time.Hour)
+	_require.Equal("1 1h0m0s", <-expired)
+	_require.Equal(0, sweeper.Len())
+
+	cancel()
+	_require.ErrorIs(<-done, context.Canceled)
+}
+
+func Test_Sweeper_Concurrent(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewManualTimeProvider(decoratorsNow)
+	var mu sync.Mutex
+	expired := map[int]bool{}
+	sweeper := NewSweeper(tp, func(key int, deadline time.Time) {
+		mu.Lock()
+		defer mu.Unlock()
+		expired[key] = true
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				key := i*100 + j
+				sweeper.Set(key, decoratorsNow.Add(time.Duration(key)*time.Second))
+				if j%10 == 0 {
+					sweeper.Remove(key)
+				}
+				sweeper.Sweep()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	tp.Advance(time.Hour)
+	sweeper.Sweep()
+	_require.Equal(0, sweeper.Len())
+	_require.Len(expired, 360)
+}
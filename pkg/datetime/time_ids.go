@@ -0,0 +1,276 @@
+package datetime
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UUID is a UUID, such as a UUIDv7 of UUIDv7Generator, with its time in the
+// first 48 bits.
+type UUID [16]byte
+
+// ULID is a ULID, https://github.com/ulid/spec, with its time in the first 48
+// bits.
+type ULID [16]byte
+
+// The max Unix milliseconds of the 48 bits of UUIDv7 and ULID, in year 10889
+const maxTimeIDMillis = 1<<48 - 1
+
+// timeIDGenerator generates the time and random parts of UUIDv7 and ULID,
+// monotonically within a millisecond: for the same or an earlier millisecond
+// than the previous ID, the previous random part is incremented instead.
+type timeIDGenerator struct {
+	tp      TimeProvider
+	entropy io.Reader
+	// randomBits of the random part, at least 64
+	randomBits int
+
+	mu     sync.Mutex
+	millis int64
+	hi     uint64 // the bits of the random part above the 64 of lo
+	lo     uint64
+}
+
+func (g *timeIDGenerator) next() (millis int64, hi, lo uint64, err error) {
+	now := g.tp.Now()
+	millis = now.UnixMilli()
+	if millis < 0 || millis > maxTimeIDMillis {
+		return 0, 0, 0, fmt.Errorf("%w: time %s is out of range of time-ordered IDs", ErrInvalidValue, TimeToISO8601DateTimeString(now))
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if millis <= g.millis {
+		// Monotonic within the millisecond, and if the clock is set back
+		millis = g.millis
+		g.lo++
+		if g.lo == 0 {
+			g.hi++
+		}
+		if g.hi < 1<<(g.randomBits-64) {
+			return g.millis, g.hi, g.lo, nil
+		}
+		// The random part overflowed, continue on the next millisecond
+		if millis++; millis > maxTimeIDMillis {
+			return 0, 0, 0, fmt.Errorf("%w: time-ordered IDs exhausted", ErrInvalidValue)
+		}
+	}
+
+	var random [16]byte
+	if _, err := io.ReadFull(g.entropy, random[:]); err != nil {
+		return 0, 0, 0, fmt.Errorf("reading entropy: %w", err)
+	}
+	g.millis = millis
+	g.hi = binary.BigEndian.Uint64(random[:8]) & (1<<(g.randomBits-64) - 1)
+	g.lo = binary.BigEndian.Uint64(random[8:])
+	return g.millis, g.hi, g.lo, nil
+}
+
+func newTimeIDGenerator(tp TimeProvider, entropy io.Reader, randomBits int) timeIDGenerator {
+	if entropy == nil {
+		entropy = rand.Reader
+	}
+	return timeIDGenerator{tp: tp, entropy: entropy, randomBits: randomBits, millis: -1}
+}
+
+func putTimeIDMillis(id []byte, millis int64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(millis))
+	copy(id[:6], b[2:])
+}
+
+func timeIDMillisToTime(id []byte) time.Time {
+	var b [8]byte
+	copy(b[2:], id[:6])
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(b[:]))).UTC()
+}
+
+// UUIDv7Generator generates UUIDv7, RFC 9562, with the time of a TimeProvider
+// and random bits of an entropy source, so that the IDs of tests are
+// reproducible. The IDs of a generator are strictly increasing, as the 74
+// random bits are incremented for the IDs of the same millisecond. It is safe
+// for concurrent use.
+type UUIDv7Generator struct {
+	g timeIDGenerator
+}
+
+// NewUUIDv7Generator returns a UUIDv7 generator on the time of tp and the
+// random bits of entropy, crypto/rand if nil.
+func NewUUIDv7Generator(tp TimeProvider, entropy io.Reader) *UUIDv7Generator {
+	return &UUIDv7Generator{g: newTimeIDGenerator(tp, entropy, 74)}
+}
+
+// New returns the next UUIDv7.
+func (g *UUIDv7Generator) New() (UUID, error) {
+	millis, hi, lo, err := g.g.next()
+	if err != nil {
+		return UUID{}, err
+	}
+
+	// 48 bits of time, 4 bits of version, 12 bits of rand_a, 2 bits of
+	// variant and 62 bits of rand_b
+	randA := hi<<2 | lo>>62
+	var id UUID
+	putTimeIDMillis(id[:], millis)
+	binary.BigEndian.PutUint16(id[6:], uint16(0x7000|randA))
+	binary.BigEndian.PutUint64(id[8:], 0x8000000000000000|lo&(1<<62-1))
+	return id, nil
+}
+
+// ParseUUID parses a UUID in the hyphenated form, such as
+// "018da6a1-387b-7c03-bf00-ff00ff00ff00", case insensitive.
+func ParseUUID(s string) (UUID, error) {
+	var id UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return id, fmt.Errorf("%w: invalid UUID %q", ErrInvalidValue, s)
+	}
+	if _, err := hex.Decode(id[:], []byte(strings.ReplaceAll(s, "-", ""))); err != nil {
+		return id, fmt.Errorf("%w: invalid UUID %q", ErrInvalidValue, s)
+	}
+	return id, nil
+}
+
+func (id UUID) String() string {
+	s := hex.EncodeToString(id[:])
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+// Version returns the version of the UUID, 7 for a UUIDv7.
+func (id UUID) Version() int {
+	return int(id[6] >> 4)
+}
+
+// Time returns the time of a UUIDv7, in UTC with millisecond precision.
+func (id UUID) Time() (time.Time, error) {
+	if id.Version() != 7 || id[8]>>6 != 0b10 {
+		return time.Time{}, fmt.Errorf("%w: UUID %s is not a UUIDv7", ErrInvalidValue, id)
+	}
+	return timeIDMillisToTime(id[:]), nil
+}
+
+// ISO8601 returns the time of a UUIDv7 as an ISO8601 string with
+// milliseconds, such as "2024-02-14T08:00:00.123Z".
+func (id UUID) ISO8601() (string, error) {
+	t, err := id.Time()
+	if err != nil {
+		return "", err
+	}
+	return FormatISO8601DateTime(t, FormatOptions{Precision: PrecisionMillis}), nil
+}
+
+func (id UUID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+func (id *UUID) UnmarshalText(text []byte) error {
+	parsed, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// ULIDGenerator generates ULIDs with the time of a TimeProvider and random
+// bits of an entropy source, so that the IDs of tests are reproducible. The
+// IDs of a generator are strictly increasing, as the 80 random bits are
+// incremented for the IDs of the same millisecond, like the monotonic ULID
+// generators. It is safe for concurrent use.
+type ULIDGenerator struct {
+	g timeIDGenerator
+}
+
+// NewULIDGenerator returns a ULID generator on the time of tp and the random
+// bits of entropy, crypto/rand if nil.
+func NewULIDGenerator(tp TimeProvider, entropy io.Reader) *ULIDGenerator {
+	return &ULIDGenerator{g: newTimeIDGenerator(tp, entropy, 80)}
+}
+
+// New returns the next ULID.
+func (g *ULIDGenerator) New() (ULID, error) {
+	millis, hi, lo, err := g.g.next()
+	if err != nil {
+		return ULID{}, err
+	}
+
+	var id ULID
+	putTimeIDMillis(id[:], millis)
+	binary.BigEndian.PutUint16(id[6:], uint16(hi))
+	binary.BigEndian.PutUint64(id[8:], lo)
+	return id, nil
+}
+
+// The Crockford base32 alphabet of ULIDs
+const ulidAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ParseULID parses a ULID, 26 characters of Crockford base32, such as
+// "01HPNQ5ZGVR7K4W3M2X1Y0ZABC", case insensitive.
+func ParseULID(s string) (ULID, error) {
+	var id ULID
+	if len(s) != 26 || s[0] > '7' {
+		return id, fmt.Errorf("%w: invalid ULID %q", ErrInvalidValue, s)
+	}
+
+	// 130 bits, of which the first 2 are 0, into 128
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		v := strings.IndexByte(ulidAlphabet, upperASCII(s[i]))
+		if v < 0 {
+			return id, fmt.Errorf("%w: invalid ULID %q", ErrInvalidValue, s)
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+	binary.BigEndian.PutUint64(id[:8], hi)
+	binary.BigEndian.PutUint64(id[8:], lo)
+	return id, nil
+}
+
+func upperASCII(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+func (id ULID) String() string {
+	hi, lo := binary.BigEndian.Uint64(id[:8]), binary.BigEndian.Uint64(id[8:])
+	var s [26]byte
+	for i := len(s) - 1; i >= 0; i-- {
+		s[i] = ulidAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(s[:])
+}
+
+// Time returns the time of the ULID, in UTC with millisecond precision.
+func (id ULID) Time() time.Time {
+	return timeIDMillisToTime(id[:])
+}
+
+// ISO8601 returns the time of the ULID as an ISO8601 string with
+// milliseconds, such as "2024-02-14T08:00:00.123Z".
+func (id ULID) ISO8601() string {
+	return FormatISO8601DateTime(id.Time(), FormatOptions{Precision: PrecisionMillis})
+}
+
+func (id ULID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+func (id *ULID) UnmarshalText(text []byte) error {
+	parsed, err := ParseULID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
@@ -0,0 +1,196 @@
+package datetime
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// seededEntropy returns reproducible random bytes.
+func seededEntropy(seed uint64) *rand.ChaCha8 {
+	var s [32]byte
+	s[0] = byte(seed)
+	return rand.NewChaCha8(s)
+}
+
+func Test_UUIDv7Generator(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewFakeTimeProvider(decoratorsNow)
+	gen := NewUUIDv7Generator(tp, bytes.NewReader(bytes.Repeat([]byte{0xff, 0x00}, 16)))
+
+	id, err := gen.New()
+	_require.Nil(err)
+	_require.Equal("018da6a1-387b-7c03-bf00-ff00ff00ff00", id.String())
+	_require.Equal(7, id.Version())
+
+	// Incremented within the millisecond
+	next, err := gen.New()
+	_require.Nil(err)
+	_require.Equal("018da6a1-387b-7c03-bf00-ff00ff00ff01", next.String())
+
+	at, err := next.Time()
+	_require.Nil(err)
+	_require.Equal(time.Date(2024, 2, 14, 8, 0, 0, 123000000, time.UTC), at)
+	s, err := next.ISO8601()
+	_require.Nil(err)
+	_require.Equal("2024-02-14T08:00:00.123Z", s)
+
+	parsed, err := ParseUUID(strings.ToUpper(next.String()))
+	_require.Nil(err)
+	_require.Equal(next, parsed)
+
+	for _, s := range []string{"", "018da6a1387b7c03bf00ff00ff00ff01", "018da6a1-387b-7c03-bf00-ff00ff00ff0g", "018da6a1-387b-7c03-bf00-ff00ff00ff010"} {
+		_, err := ParseUUID(s)
+		_require.ErrorIs(err, ErrInvalidValue, s)
+	}
+
+	v4, err := ParseUUID("f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	_require.Nil(err)
+	_require.Equal(4, v4.Version())
+	_, err = v4.Time()
+	_require.ErrorIs(err, ErrInvalidValue)
+	_, err = v4.ISO8601()
+	_require.ErrorIs(err, ErrInvalidValue)
+
+	data, err := json.Marshal(map[string]UUID{"id": id})
+	_require.Nil(err)
+	_require.Equal(`{"id":"018da6a1-387b-7c03-bf00-ff00ff00ff00"}`, string(data))
+	var decoded map[string]UUID
+	_require.Nil(json.Unmarshal(data, &decoded))
+	_require.Equal(id, decoded["id"])
+}
+
+func Test_ULIDGenerator(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewFakeTimeProvider(decoratorsNow)
+	gen := NewULIDGenerator(tp, bytes.NewReader(bytes.Repeat([]byte{0xff}, 32)))
+
+	id, err := gen.New()
+	_require.Nil(err)
+	_require.Equal("01HPKA2E3VZZZZZZZZZZZZZZZZ", id.String())
+	_require.Equal(time.Date(2024, 2, 14, 8, 0, 0, 123000000, time.UTC), id.Time())
+	_require.Equal("2024-02-14T08:00:00.123Z", id.ISO8601())
+
+	// The random part overflows, so continues on the next millisecond
+	next, err := gen.New()
+	_require.Nil(err)
+	_require.Equal("01HPKA2E3WZZZZZZZZZZZZZZZZ", next.String())
+	_require.Equal("2024-02-14T08:00:00.124Z", next.ISO8601())
+
+	parsed, err := ParseULID(strings.ToLower(next.String()))
+	_require.Nil(err)
+	_require.Equal(next, parsed)
+
+	for _, s := range []string{"", "01HPKA2E3WZZZZZZZZZZZZZZZ", "81HPKWDD6WZZZZZZZZZZZZZZZZ", "01HPKA2E3WZZZZZZZZZZZZZZZU", "01HPKA2E3WZZZZZZZZZZZZZZZI"} {
+		_, err := ParseULID(s)
+		_require.ErrorIs(err, ErrInvalidValue, s)
+	}
+
+	// The max ULID
+	parsed, err = ParseULID("7ZZZZZZZZZZZZZZZZZZZZZZZZZ")
+	_require.Nil(err)
+	_require.Equal(ULID(bytes.Repeat([]byte{0xff}, 16)), parsed)
+
+	var decoded struct{ ID ULID }
+	_require.Nil(json.Unmarshal([]byte(`{"ID":"01HPKA2E3VZZZZZZZZZZZZZZZZ"}`), &decoded))
+	_require.Equal(id, decoded.ID)
+}
+
+func Test_TimeIDGenerators_Monotonic(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewManualTimeProvider(decoratorsNow)
+	uuids := NewUUIDv7Generator(tp, seededEntropy(1))
+	ulids := NewULIDGenerator(tp, seededEntropy(1))
+
+	var uuidStrings, ulidStrings []string
+	for i := 0; i < 1000; i++ {
+		switch i % 100 {
+		case 10:
+			tp.Advance(time.Millisecond)
+		case 20:
+			tp.Set(tp.Now().Add(-time.Second)) // set back
+		case 30:
+			tp.Advance(2 * time.Second)
+		}
+		u, err := uuids.New()
+		_require.Nil(err)
+		l, err := ulids.New()
+		_require.Nil(err)
+		uuidStrings = append(uuidStrings, u.String())
+		ulidStrings = append(ulidStrings, l.String())
+
+		ut, err := u.Time()
+		_require.Nil(err)
+		_require.Equal(ut, l.Time())
+	}
+
+	// Strictly increasing, in both the bytes and the string forms
+	for _, ids := range [][]string{uuidStrings, ulidStrings} {
+		_require.True(sort.StringsAreSorted(ids))
+		for i := 1; i < len(ids); i++ {
+			_require.NotEqual(ids[i-1], ids[i])
+		}
+	}
+
+	// Reproducible with the same entropy
+	again := NewULIDGenerator(NewFakeTimeProvider(decoratorsNow), seededEntropy(1))
+	first, err := again.New()
+	_require.Nil(err)
+	_require.Equal(ulidStrings[0], first.String())
+}
+
+func Test_TimeIDGenerators_Errors(t *testing.T) {
+	_require := require.New(t)
+
+	_, err := NewUUIDv7Generator(NewFakeTimeProvider(time.Unix(-1, 0)), nil).New()
+	_require.ErrorIs(err, ErrInvalidValue)
+	_, err = NewULIDGenerator(NewFakeTimeProvider(time.Date(10890, 1, 1, 0, 0, 0, 0, time.UTC)), nil).New()
+	_require.ErrorIs(err, ErrInvalidValue)
+
+	failing := errors.New("no entropy")
+	_, err = NewULIDGenerator(NewUTCTimeProvider(), iotestErrReader{failing}).New()
+	_require.ErrorIs(err, failing)
+
+	// crypto/rand by default, concurrently
+	gen := NewUUIDv7Generator(NewUTCTimeProvider(), nil)
+	var mu sync.Mutex
+	seen := map[UUID]bool{}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 250; j++ {
+				id, err := gen.New()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	_require.Len(seen, 1000)
+}
+
+type iotestErrReader struct {
+	err error
+}
+
+func (r iotestErrReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,108 @@
+package datetime
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"time"
+
+	todpb "google.golang.org/genproto/googleapis/type/timeofday"
+)
+
+// TimeOfDay is a wall clock time without a date or a location, like the
+// google.type.TimeOfDay proto and the SQL "time" type.
+type TimeOfDay struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+}
+
+// NewTimeOfDay returns the TimeOfDay for the given hour, minute and second.
+func NewTimeOfDay(hour, minute, second int) TimeOfDay {
+	return TimeOfDay{Hour: hour, Minute: minute, Second: second}
+}
+
+// TimeOfDayOf returns the wall clock time of t in its own location.
+func TimeOfDayOf(t time.Time) TimeOfDay {
+	return TimeOfDay{
+		Hour:       t.Hour(),
+		Minute:     t.Minute(),
+		Second:     t.Second(),
+		Nanosecond: t.Nanosecond(),
+	}
+}
+
+// ParseTimeOfDay parses a "15:04", "15:04:05" or "15:04:05.999999999"
+// formatted time of day, as returned by SQL "time" columns.
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	layout := "15:04:05.999999999"
+	if strings.Count(s, ":") == 1 {
+		layout = "15:04"
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return TimeOfDay{}, fmt.Errorf("%w: %q is not a time of day", ErrInvalidValue, s)
+	}
+	return TimeOfDayOf(t), nil
+}
+
+// IsValid reports whether all fields of t are within their normal ranges.
+func (t TimeOfDay) IsValid() bool {
+	return t.Hour >= 0 && t.Hour < 24 &&
+		t.Minute >= 0 && t.Minute < 60 &&
+		t.Second >= 0 && t.Second < 60 &&
+		t.Nanosecond >= 0 && t.Nanosecond < int(time.Second)
+}
+
+// On returns the instant at which the wall clock in location l shows t on the
+// date d.
+func (t TimeOfDay) On(d Date, l *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, t.Hour, t.Minute, t.Second, t.Nanosecond, l)
+}
+
+// SinceMidnight returns the wall clock duration from midnight to t.
+func (t TimeOfDay) SinceMidnight() time.Duration {
+	return time.Duration(t.Hour)*time.Hour +
+		time.Duration(t.Minute)*time.Minute +
+		time.Duration(t.Second)*time.Second +
+		time.Duration(t.Nanosecond)
+}
+
+// Compare returns -1, 0 or +1 depending on whether t is before, equal to or
+// after other.
+func (t TimeOfDay) Compare(other TimeOfDay) int {
+	return cmp.Compare(t.SinceMidnight(), other.SinceMidnight())
+}
+
+// String returns t formatted as "15:04:05", with a fraction of a second only
+// when t has one.
+func (t TimeOfDay) String() string {
+	return t.On(Date{Year: 1, Month: time.January, Day: 1}, time.UTC).Format("15:04:05.999999999")
+}
+
+// ProtoTimeOfDayToTimeOfDay returns a new TimeOfDay based on the
+// google.type.TimeOfDay.
+func ProtoTimeOfDayToTimeOfDay(t *todpb.TimeOfDay) (TimeOfDay, error) {
+	if t == nil {
+		return TimeOfDay{}, fmt.Errorf("%w: time of day parameter not set", ErrInvalidValue)
+	}
+
+	return TimeOfDay{
+		Hour:       int(t.GetHours()),
+		Minute:     int(t.GetMinutes()),
+		Second:     int(t.GetSeconds()),
+		Nanosecond: int(t.GetNanos()),
+	}, nil
+}
+
+// TimeOfDayToProtoTimeOfDay returns a new google.type.TimeOfDay based on the
+// TimeOfDay.
+func TimeOfDayToProtoTimeOfDay(t TimeOfDay) *todpb.TimeOfDay {
+	return &todpb.TimeOfDay{
+		Hours:   int32(t.Hour),
+		Minutes: int32(t.Minute),
+		Seconds: int32(t.Second),
+		Nanos:   int32(t.Nanosecond),
+	}
+}
@@ -1,6 +1,8 @@
 package datetime
 
 import (
+	"context"
+	"sync"
 	"time"
 )
 
@@ -30,3 +32,138 @@ func (f *fakeTimeProvider) Now() time.Time {
 func NewFakeTimeProvider(now time.Time) TimeProvider {
 	return &fakeTimeProvider{now: now}
 }
+
+// Sleeper is implemented by the TimeProviders that can wait for their own
+// time, such as ManualTimeProvider, instead of the real clock.
+type Sleeper interface {
+	// SleepUntil returns nil once the time of the provider is t or later, or
+	// the error of ctx if it is done before
+	SleepUntil(ctx context.Context, t time.Time) error
+}
+
+// SleepUntil waits until the time of tp is t or later, or ctx is done. It
+// waits with tp if it is a Sleeper, otherwise with real timers, checking tp
+// at least every minute, so that providers ahead of or behind the real clock,
+// such as Offset, are waited for too.
+func SleepUntil(ctx context.Context, tp TimeProvider, t time.Time) error {
+	if sleeper, ok := tp.(Sleeper); ok {
+		return sleeper.SleepUntil(ctx, t)
+	}
+	for {
+		d := t.Sub(tp.Now())
+		if d <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(min(d, time.Minute))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// sleepUntilDone waits until ctx is done, with tp if it is a Sleeper, so that
+// simulations see the wait too.
+func sleepUntilDone(ctx context.Context, tp TimeProvider) {
+	if sleeper, ok := tp.(Sleeper); ok {
+		_ = sleeper.SleepUntil(ctx, endOfTime)
+		return
+	}
+	<-ctx.Done()
+}
+
+// endOfTime is after any time waited for.
+var endOfTime = time.Date(9999, 12, 31, 23, 59, 59, 999999999, time.UTC)
+
+type manualSleeper struct {
+	until time.Time
+	wake  chan struct{}
+}
+
+// ManualTimeProvider is a fake TimeProvider whose time is set by the test,
+// and that is a Sleeper, so that code waiting for the time, such as
+// CronScheduler, can be tested without real waiting. It is safe for
+// concurrent use.
+type ManualTimeProvider struct {
+	mu       sync.Mutex
+	now      time.Time
+	sleepers []*manualSleeper
+}
+
+// NewManualTimeProvider returns a provider of the time now, until Set or
+// Advance.
+func NewManualTimeProvider(now time.Time) *ManualTimeProvider {
+	return &ManualTimeProvider{now: now}
+}
+
+func (m *ManualTimeProvider) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+// Set sets the time, and wakes the sleepers until then.
+func (m *ManualTimeProvider) Set(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.set(now)
+}
+
+// Advance moves the time forward by d, and wakes the sleepers until then.
+func (m *ManualTimeProvider) Advance(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.set(m.now.Add(d))
+}
+
+// set sets the time and wakes the sleepers, the caller must hold m.mu.
+func (m *ManualTimeProvider) set(now time.Time) {
+	m.now = now
+	sleepers := m.sleepers[:0]
+	for _, sleeper := range m.sleepers {
+		if now.Before(sleeper.until) {
+			sleepers = append(sleepers, sleeper)
+		} else {
+			close(sleeper.wake)
+		}
+	}
+	m.sleepers = sleepers
+}
+
+// SleepUntil returns nil once the time is set to t or later, or the error of
+// ctx if it is done before.
+func (m *ManualTimeProvider) SleepUntil(ctx context.Context, t time.Time) error {
+	m.mu.Lock()
+	if !m.now.Before(t) {
+		m.mu.Unlock()
+		return nil
+	}
+	sleeper := &manualSleeper{until: t, wake: make(chan struct{})}
+	m.sleepers = append(m.sleepers, sleeper)
+	m.mu.Unlock()
+
+	select {
+	case <-sleeper.wake:
+		return nil
+	case <-ctx.Done():
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for i, s := range m.sleepers {
+			if s == sleeper {
+				m.sleepers = append(m.sleepers[:i], m.sleepers[i+1:]...)
+				break
+			}
+		}
+		return ctx.Err()
+	}
+}
+
+// Sleepers returns the number of goroutines in SleepUntil, such as to wait
+// until the code under test waits before advancing the time.
+func (m *ManualTimeProvider) Sleepers() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sleepers)
+}
@@ -0,0 +1,127 @@
+package datetime
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// The decorators below wrap a TimeProvider and compose, such as running a
+// staging service a week ahead, at whole seconds, in Stockholm:
+//
+//	tp := InLocation(Truncated(Offset(NewUTCTimeProvider(), 7*24*time.Hour), time.Second), stockholm)
+
+type offsetTimeProvider struct {
+	tp TimeProvider
+	d  time.Duration
+}
+
+func (o *offsetTimeProvider) Now() time.Time {
+	return o.tp.Now().Add(o.d)
+}
+
+// Offset returns a TimeProvider that is d ahead of tp, or behind if d is
+// negative.
+func Offset(tp TimeProvider, d time.Duration) TimeProvider {
+	return &offsetTimeProvider{tp: tp, d: d}
+}
+
+// Frozen returns a TimeProvider that always returns the time of tp when
+// Frozen is called.
+func Frozen(tp TimeProvider) TimeProvider {
+	return NewFakeTimeProvider(tp.Now())
+}
+
+type scaledTimeProvider struct {
+	tp     TimeProvider
+	factor float64
+	epoch  time.Time
+}
+
+func (s *scaledTimeProvider) Now() time.Time {
+	elapsed := float64(s.tp.Now().Sub(s.epoch)) * s.factor
+	// Saturated like time.Time.Sub, as converting out of range floats is
+	// undefined
+	switch {
+	case elapsed >= math.MaxInt64:
+		return s.epoch.Add(math.MaxInt64)
+	case elapsed <= math.MinInt64:
+		return s.epoch.Add(math.MinInt64)
+	}
+	return s.epoch.Add(time.Duration(elapsed))
+}
+
+// Scaled returns a TimeProvider where time passes factor times as fast as for
+// tp since epoch, such as 60 for an hour per minute. Both return epoch at
+// epoch. The scaled time saturates about 292 years from epoch, the range of a
+// time.Duration. Scaled panics if factor is negative, infinite or NaN.
+func Scaled(tp TimeProvider, factor float64, epoch time.Time) TimeProvider {
+	if factor < 0 || math.IsInf(factor, 0) || math.IsNaN(factor) {
+		panic(fmt.Sprintf("datetime: invalid Scaled factor %v", factor))
+	}
+	return &scaledTimeProvider{tp: tp, factor: factor, epoch: epoch}
+}
+
+type skewedTimeProvider struct {
+	tp        TimeProvider
+	maxJitter time.Duration
+	mu        sync.Mutex
+	rand      *rand.Rand
+}
+
+func (s *skewedTimeProvider) Now() time.Time {
+	if s.maxJitter <= 0 {
+		return s.tp.Now()
+	}
+
+	var jitter int64
+	if s.rand == nil {
+		jitter = rand.Int64N(2*int64(s.maxJitter) + 1)
+	} else {
+		s.mu.Lock()
+		jitter = s.rand.Int64N(2*int64(s.maxJitter) + 1)
+		s.mu.Unlock()
+	}
+	return s.tp.Now().Add(time.Duration(jitter) - s.maxJitter)
+}
+
+// Skewed returns a TimeProvider that adds a random jitter between -maxJitter
+// and maxJitter to each time of tp, so that consecutive times may go
+// backwards, like unsynchronized clocks. The jitter is drawn from r, seeded
+// for reproducible tests, or from the global source of math/rand/v2 if r is
+// nil.
+func Skewed(tp TimeProvider, maxJitter time.Duration, r *rand.Rand) TimeProvider {
+	return &skewedTimeProvider{tp: tp, maxJitter: maxJitter, rand: r}
+}
+
+type truncatedTimeProvider struct {
+	tp        TimeProvider
+	precision time.Duration
+}
+
+func (t *truncatedTimeProvider) Now() time.Time {
+	return t.tp.Now().Truncate(t.precision)
+}
+
+// Truncated returns a TimeProvider that truncates the times of tp to a
+// multiple of precision since the zero time, such as time.Millisecond to
+// match the precision of a database.
+func Truncated(tp TimeProvider, precision time.Duration) TimeProvider {
+	return &truncatedTimeProvider{tp: tp, precision: precision}
+}
+
+type locationTimeProvider struct {
+	tp  TimeProvider
+	loc *time.Location
+}
+
+func (l *locationTimeProvider) Now() time.Time {
+	return l.tp.Now().In(l.loc)
+}
+
+// InLocation returns a TimeProvider that returns the times of tp in loc.
+func InLocation(tp TimeProvider, loc *time.Location) TimeProvider {
+	return &locationTimeProvider{tp: tp, loc: loc}
+}
@@ -0,0 +1,146 @@
+package datetime
+
+import (
+	"math"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Offset(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewManualTimeProvider(decoratorsNow)
+	weekAhead := Offset(tp, 7*24*time.Hour)
+	_require.Equal(decoratorsNow.AddDate(0, 0, 7), weekAhead.Now())
+
+	tp.Advance(time.Hour)
+	_require.Equal(decoratorsNow.AddDate(0, 0, 7).Add(time.Hour), weekAhead.Now())
+
+	_require.Equal(decoratorsNow.Add(-time.Minute), Offset(tp, -time.Hour-time.Minute).Now())
+}
+
+func Test_Frozen(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewManualTimeProvider(decoratorsNow)
+	frozen := Frozen(tp)
+	tp.Advance(time.Hour)
+	_require.Equal(decoratorsNow, frozen.Now())
+	_require.Equal(decoratorsNow, frozen.Now())
+
+	// Of the real clock
+	frozen = Frozen(NewUTCTimeProvider())
+	_require.Equal(frozen.Now(), frozen.Now())
+}
+
+func Test_Scaled(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewManualTimeProvider(decoratorsNow)
+	epoch := decoratorsNow
+	hourPerMinute := Scaled(tp, 60, epoch)
+	_require.Equal(epoch, hourPerMinute.Now())
+
+	tp.Set(epoch.Add(time.Minute))
+	_require.Equal(epoch.Add(time.Hour), hourPerMinute.Now())
+	tp.Set(epoch.Add(-time.Second))
+	_require.Equal(epoch.Add(-time.Minute), hourPerMinute.Now())
+
+	tp.Set(epoch.Add(time.Hour))
+	_require.Equal(epoch.Add(30*time.Minute), Scaled(tp, 0.5, epoch).Now())
+	_require.Equal(epoch, Scaled(tp, 0, epoch).Now())
+
+	// Saturated instead of overflowing
+	tp.Set(epoch.Add(100 * 24 * time.Hour))
+	_require.Equal(epoch.Add(math.MaxInt64), Scaled(tp, 1e6, epoch).Now())
+	tp.Set(epoch.Add(-100 * 24 * time.Hour))
+	_require.Equal(epoch.Add(math.MinInt64), Scaled(tp, 1e6, epoch).Now())
+
+	_require.Panics(func() { Scaled(tp, -1, epoch) })
+	_require.Panics(func() { Scaled(tp, math.Inf(1), epoch) })
+	_require.Panics(func() { Scaled(tp, math.NaN(), epoch) })
+}
+
+func Test_Skewed(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewFakeTimeProvider(decoratorsNow)
+	maxJitter := 50 * time.Millisecond
+
+	seen := map[time.Time]bool{}
+	for _, r := range []*rand.Rand{rand.New(rand.NewPCG(1, 2)), nil} {
+		skewed := Skewed(tp, maxJitter, r)
+		for i := 0; i < 1000; i++ {
+			now := skewed.Now()
+			_require.WithinDuration(decoratorsNow, now, maxJitter)
+			seen[now] = true
+		}
+	}
+	_require.Greater(len(seen), 100)
+
+	// Reproducible with a seeded source
+	times := func() []time.Time {
+		skewed := Skewed(tp, maxJitter, rand.New(rand.NewPCG(1, 2)))
+		return []time.Time{skewed.Now(), skewed.Now(), skewed.Now()}
+	}
+	_require.Equal(times(), times())
+
+	_require.Equal(decoratorsNow, Skewed(tp, 0, nil).Now())
+
+	// Safe for concurrent use with a source
+	skewed := Skewed(tp, maxJitter, rand.New(rand.NewPCG(1, 2)))
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				skewed.Now()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func Test_Truncated(t *testing.T) {
+	_require := require.New(t)
+
+	tp := NewFakeTimeProvider(decoratorsNow)
+	_require.Equal(time.Date(2024, 2, 14, 8, 0, 0, 123000000, time.UTC), Truncated(tp, time.Millisecond).Now())
+	_require.Equal(time.Date(2024, 2, 14, 8, 0, 0, 0, time.UTC), Truncated(tp, time.Second).Now())
+	_require.Equal(decoratorsNow, Truncated(tp, 0).Now())
+}
+
+func Test_InLocation(t *testing.T) {
+	_require := require.New(t)
+
+	stockholm, err := LoadLocation("Europe/Stockholm")
+	_require.Nil(err)
+
+	now := InLocation(NewFakeTimeProvider(decoratorsNow), stockholm).Now()
+	_require.Equal(stockholm, now.Location())
+	_require.True(decoratorsNow.Equal(now))
+	_require.Equal(9, now.Hour())
+}
+
+func Test_TimeProviderDecorators_Compose(t *testing.T) {
+	_require := require.New(t)
+
+	stockholm, err := LoadLocation("Europe/Stockholm")
+	_require.Nil(err)
+
+	tp := NewManualTimeProvider(decoratorsNow)
+	composed := InLocation(Truncated(Offset(Scaled(tp, 60, decoratorsNow), 7*24*time.Hour), time.Second), stockholm)
+	tp.Advance(2 * time.Minute)
+
+	_require.Equal("2024-02-21T11:00:00+01:00", composed.Now().Format(time.RFC3339Nano))
+
+	frozen := Frozen(composed)
+	tp.Advance(time.Minute)
+	_require.Equal("2024-02-21T11:00:00+01:00", frozen.Now().Format(time.RFC3339Nano))
+	_require.Equal("2024-02-21T12:00:00+01:00", composed.Now().Format(time.RFC3339Nano))
+}
@@ -0,0 +1,6 @@
+package datetime
+
+import "time"
+
+// decoratorsNow is the time of the fake TimeProviders shared by the tests.
+var decoratorsNow = time.Date(2024, 2, 14, 8, 0, 0, 123456789, time.UTC)
@@ -1,6 +1,7 @@
 package datetime
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -13,3 +14,70 @@ func Test_UTCTimeProvider(t *testing.T) {
 	timeProvider := NewUTCTimeProvider()
 	require.Equal(time.UTC, timeProvider.Now().Location())
 }
+
+func Test_ManualTimeProvider(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2024, 2, 14, 8, 0, 0, 0, time.UTC)
+	tp := NewManualTimeProvider(now)
+	require.Equal(now, tp.Now())
+	tp.Advance(time.Hour)
+	require.Equal(now.Add(time.Hour), tp.Now())
+	tp.Set(now)
+	require.Equal(now, tp.Now())
+
+	// Not sleeping for times passed
+	require.Nil(tp.SleepUntil(context.Background(), now))
+
+	woken := make(chan time.Time, 2)
+	for _, d := range []time.Duration{time.Minute, time.Hour} {
+		go func(until time.Time) {
+			if err := tp.SleepUntil(context.Background(), until); err == nil {
+				woken <- until
+			}
+		}(now.Add(d))
+	}
+	require.Eventually(func() bool { return tp.Sleepers() == 2 }, time.Second, time.Millisecond)
+
+	tp.Advance(59 * time.Second)
+	require.Equal(2, tp.Sleepers())
+	tp.Advance(time.Second)
+	require.Equal(now.Add(time.Minute), <-woken)
+	require.Equal(1, tp.Sleepers())
+	tp.Advance(2 * time.Hour)
+	require.Equal(now.Add(time.Hour), <-woken)
+	require.Equal(0, tp.Sleepers())
+
+	// Cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- tp.SleepUntil(ctx, now.Add(24*time.Hour)) }()
+	require.Eventually(func() bool { return tp.Sleepers() == 1 }, time.Second, time.Millisecond)
+	cancel()
+	require.ErrorIs(<-done, context.Canceled)
+	require.Equal(0, tp.Sleepers())
+
+	var _ Sleeper = tp
+}
+
+func Test_SleepUntil(t *testing.T) {
+	require := require.New(t)
+
+	// With real timers, for a provider that isn't a Sleeper
+	tp := Offset(NewUTCTimeProvider(), 24*time.Hour)
+	until := tp.Now().Add(20 * time.Millisecond)
+	require.Nil(SleepUntil(context.Background(), tp, until))
+	require.False(tp.Now().Before(until))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(SleepUntil(ctx, tp, tp.Now().Add(time.Hour)), context.DeadlineExceeded)
+
+	// With the provider
+	manual := NewManualTimeProvider(time.Date(2024, 2, 14, 8, 0, 0, 0, time.UTC))
+	done := make(chan error)
+	go func() { done <- SleepUntil(context.Background(), manual, manual.Now().Add(time.Hour)) }()
+	require.Eventually(func() bool { return manual.Sleepers() == 1 }, time.Second, time.Millisecond)
+	manual.Advance(time.Hour)
+	require.Nil(<-done)
+}
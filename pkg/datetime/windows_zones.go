@@ -0,0 +1,205 @@
+package datetime
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The mapping of Windows zone names to IANA zones is embedded from the CLDR
+// windowsZones.xml. The first IANA zone of each Windows zone is its CLDR
+// "001" zone, followed by the zones of the other territories it covers.
+//
+//go:embed zones/windows_zones.json
+var windowsZonesFile []byte
+
+var (
+	// Windows zone name to IANA zones, as in windows_zones.json
+	windowsZones map[string][]string
+	// Canonical IANA zone ID to Windows zone name
+	ianaToWindows map[string]string
+	windowsOnce   sync.Once
+)
+
+func loadWindowsZones() {
+	windowsOnce.Do(func() {
+		data := struct {
+			Zones map[string][]string `json:"zones"`
+		}{}
+		if err := json.Unmarshal(windowsZonesFile, &data); err != nil {
+			panic(fmt.Sprintf("zones/windows_zones.json: %v", err))
+		}
+		windowsZones = data.Zones
+
+		names := make([]string, 0, len(windowsZones))
+		for name := range windowsZones {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		// The "001" zones first, so that they win over the territory zones
+		ianaToWindows = make(map[string]string)
+		for _, name := range names {
+			ianaToWindows[CanonicalZone(windowsZones[name][0])] = name
+		}
+		for _, name := range names {
+			for _, zone := range windowsZones[name][1:] {
+				if _, ok := ianaToWindows[CanonicalZone(zone)]; !ok {
+					ianaToWindows[CanonicalZone(zone)] = name
+				}
+			}
+		}
+	})
+}
+
+// IANAFromWindows returns the canonical IANA zone ID of a Windows zone name,
+// such as "Europe/Berlin" for "W. Europe Standard Time".
+func IANAFromWindows(windowsName string) (string, error) {
+	loadWindowsZones()
+	zones, ok := windowsZones[windowsName]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown Windows time zone %q", ErrInvalidValue, windowsName)
+	}
+	return CanonicalZone(zones[0]), nil
+}
+
+// WindowsFromIANA returns the Windows zone name of an IANA zone ID or alias,
+// such as "W. Europe Standard Time" for "Europe/Stockholm".
+func WindowsFromIANA(zoneID string) (string, error) {
+	loadWindowsZones()
+	name, ok := ianaToWindows[CanonicalZone(zoneID)]
+	if !ok {
+		return "", fmt.Errorf("%w: no Windows time zone for %q", ErrInvalidValue, zoneID)
+	}
+	return name, nil
+}
+
+// ErrAmbiguousZone is wrapped by the errors of ZoneResolver for a zone
+// abbreviation used by several zones, such as "IST".
+var ErrAmbiguousZone = errors.New("ambiguous time zone")
+
+// The zones of the common abbreviations resolved by ZoneResolver. An
+// abbreviation used by zones with different rules has all of them.
+var zoneAbbreviations = map[string][]string{
+	"UTC":  {"UTC"},
+	"GMT":  {"UTC"},
+	"Z":    {"UTC"},
+	"WET":  {"Europe/Lisbon"},
+	"WEST": {"Europe/Lisbon"},
+	"CET":  {"Europe/Berlin"},
+	"CEST": {"Europe/Berlin"},
+	"EET":  {"Europe/Helsinki"},
+	"EEST": {"Europe/Helsinki"},
+	"MSK":  {"Europe/Moscow"},
+	"BST":  {"Europe/London", "Asia/Dhaka"},
+	"IST":  {"Asia/Kolkata", "Europe/Dublin", "Asia/Jerusalem"},
+	"EST":  {"America/New_York"},
+	"EDT":  {"America/New_York"},
+	"CST":  {"America/Chicago", "Asia/Shanghai", "America/Havana"},
+	"CDT":  {"America/Chicago", "America/Havana"},
+	"MST":  {"America/Denver", "America/Phoenix"},
+	"MDT":  {"America/Denver"},
+	"PST":  {"America/Los_Angeles", "Asia/Manila"},
+	"PDT":  {"America/Los_Angeles"},
+	"AKST": {"America/Anchorage"},
+	"AKDT": {"America/Anchorage"},
+	"HST":  {"Pacific/Honolulu"},
+	"AST":  {"America/Halifax", "Asia/Riyadh"},
+	"ADT":  {"America/Halifax"},
+	"NST":  {"America/St_Johns"},
+	"NDT":  {"America/St_Johns"},
+	"SAST": {"Africa/Johannesburg"},
+	"JST":  {"Asia/Tokyo"},
+	"KST":  {"Asia/Seoul"},
+	"HKT":  {"Asia/Hong_Kong"},
+	"AEST": {"Australia/Sydney", "Australia/Brisbane"},
+	"AEDT": {"Australia/Sydney"},
+	"NZST": {"Pacific/Auckland"},
+	"NZDT": {"Pacific/Auckland"},
+}
+
+// ZoneResolver resolves zone IDs that are not IANA zone IDs, as sent by some
+// integrations, to locations. The zero value only resolves IANA zone IDs, like
+// LoadLocation.
+type ZoneResolver struct {
+	// Windows resolves Windows zone names, such as "W. Europe Standard Time"
+	Windows bool
+	// Abbreviations resolves common zone abbreviations, such as "CET" or "EST",
+	// to the zone using them. They are resolved before the legacy IANA zones
+	// with the same names, such as "EST" which has no DST.
+	Abbreviations bool
+	// POSIX resolves POSIX TZ strings, such as "CET-1CEST,M3.5.0,M10.5.0/3",
+	// that are not IANA zone IDs
+	POSIX bool
+	// Preferred resolves ambiguous abbreviations to an IANA zone ID, such as
+	// {"IST": "Asia/Kolkata"}, instead of failing with ErrAmbiguousZone. The
+	// abbreviations are case insensitive.
+	Preferred map[string]string
+}
+
+// LenientZoneResolver resolves IANA zone IDs, Windows zone names,
+// abbreviations and POSIX TZ strings.
+var LenientZoneResolver = ZoneResolver{Windows: true, Abbreviations: true, POSIX: true}
+
+// Resolve returns the location of the zone id. The errors wrap
+// ErrInvalidValue, and also ErrAmbiguousZone for an ambiguous abbreviation.
+func (r ZoneResolver) Resolve(id string) (*time.Location, error) {
+	id = strings.TrimSpace(id)
+
+	if r.Abbreviations {
+		abbreviation := strings.ToUpper(id)
+		if preferred, ok := r.preferred(abbreviation); ok {
+			return r.load(id, preferred)
+		}
+		if zones, ok := zoneAbbreviations[abbreviation]; ok {
+			if len(zones) > 1 {
+				return nil, fmt.Errorf("%w: %w: %q can be any of %s", ErrInvalidValue, ErrAmbiguousZone, id, strings.Join(zones, ", "))
+			}
+			return r.load(id, zones[0])
+		}
+	}
+
+	if r.Windows {
+		if zoneID, err := IANAFromWindows(id); err == nil {
+			return r.load(id, zoneID)
+		}
+	}
+
+	l, err := r.load(id, id)
+	if err != nil && r.POSIX {
+		if l, posixErr := LocationFromPOSIXTZ(id); posixErr == nil {
+			return l, nil
+		}
+	}
+	return l, err
+}
+
+// preferred returns the zone of Preferred for the upper case abbreviation,
+// whatever the case of the key.
+func (r ZoneResolver) preferred(abbreviation string) (string, bool) {
+	if zone, ok := r.Preferred[abbreviation]; ok {
+		return zone, true
+	}
+	for key, zone := range r.Preferred {
+		if strings.EqualFold(key, abbreviation) {
+			return zone, true
+		}
+	}
+	return "", false
+}
+
+func (r ZoneResolver) load(id, zoneID string) (*time.Location, error) {
+	if zoneID == "" || zoneID == "Local" {
+		return nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidValue, id)
+	}
+	l, err := LoadLocation(zoneID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidValue, id)
+	}
+	return l, nil
+}
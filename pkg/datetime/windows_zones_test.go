@@ -0,0 +1,124 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	dtpb "google.golang.org/genproto/googleapis/type/datetime"
+)
+
+func Test_IANAFromWindows(t *testing.T) {
+	_require := require.New(t)
+
+	zoneID, err := IANAFromWindows("W. Europe Standard Time")
+	_require.Nil(err)
+	_require.Equal("Europe/Berlin", zoneID)
+
+	// CLDR still uses the alias
+	zoneID, err = IANAFromWindows("FLE Standard Time")
+	_require.Nil(err)
+	_require.Equal("Europe/Kyiv", zoneID)
+
+	_, err = IANAFromWindows("Stockholm Standard Time")
+	_require.ErrorIs(err, ErrInvalidValue)
+
+	// All of them can be loaded
+	loadWindowsZones()
+	for name, zones := range windowsZones {
+		for _, zone := range zones {
+			_, err := LoadLocation(zone)
+			_require.Nil(err, "%s: %s", name, zone)
+		}
+	}
+}
+
+func Test_WindowsFromIANA(t *testing.T) {
+	_require := require.New(t)
+
+	for zoneID, expected := range map[string]string{
+		"Europe/Stockholm":    "W. Europe Standard Time",
+		"Europe/Berlin":       "W. Europe Standard Time",
+		"Europe/Copenhagen":   "Romance Standard Time",
+		"Europe/Helsinki":     "FLE Standard Time",
+		"Europe/Kiev":         "FLE Standard Time",
+		"America/New_York":    "Eastern Standard Time",
+		"America/Los_Angeles": "Pacific Standard Time",
+		"UTC":                 "UTC",
+	} {
+		name, err := WindowsFromIANA(zoneID)
+		_require.Nil(err, zoneID)
+		_require.Equal(expected, name, zoneID)
+	}
+
+	_, err := WindowsFromIANA("Mars/Olympus_Mons")
+	_require.ErrorIs(err, ErrInvalidValue)
+}
+
+func Test_ZoneResolver(t *testing.T) {
+	_require := require.New(t)
+
+	l, err := LenientZoneResolver.Resolve("W. Europe Standard Time")
+	_require.Nil(err)
+	_require.Equal("Europe/Berlin", l.String())
+
+	l, err = LenientZoneResolver.Resolve("cet")
+	_require.Nil(err)
+	_require.Equal("Europe/Berlin", l.String())
+
+	// Unlike the legacy IANA zone, EST has DST
+	l, err = LenientZoneResolver.Resolve("EST")
+	_require.Nil(err)
+	_require.Equal("America/New_York", l.String())
+	l, err = ZoneResolver{}.Resolve("EST")
+	_require.Nil(err)
+	_require.Equal("EST", l.String())
+
+	l, err = LenientZoneResolver.Resolve("Europe/Stockholm")
+	_require.Nil(err)
+	_require.Equal("Europe/Stockholm", l.String())
+
+	_, err = LenientZoneResolver.Resolve("IST")
+	_require.ErrorIs(err, ErrAmbiguousZone)
+	_require.ErrorIs(err, ErrInvalidValue)
+	_require.Contains(err.Error(), "Asia/Kolkata, Europe/Dublin, Asia/Jerusalem")
+
+	resolver := LenientZoneResolver
+	resolver.Preferred = map[string]string{"IST": "Asia/Kolkata", "cst": "America/Chicago"}
+	l, err = resolver.Resolve("IST")
+	_require.Nil(err)
+	_require.Equal("Asia/Kolkata", l.String())
+	l, err = resolver.Resolve("CST")
+	_require.Nil(err)
+	_require.Equal("America/Chicago", l.String())
+
+	_, err = ZoneResolver{}.Resolve("W. Europe Standard Time")
+	_require.ErrorIs(err, ErrInvalidValue)
+	_, err = LenientZoneResolver.Resolve("")
+	_require.ErrorIs(err, ErrInvalidValue)
+}
+
+func Test_ProtoDateTimeToTimeWithResolver(t *testing.T) {
+	_require := require.New(t)
+
+	d := &dtpb.DateTime{
+		Year: 2024, Month: 2, Day: 14, Hours: 9,
+		TimeOffset: &dtpb.DateTime_TimeZone{TimeZone: &dtpb.TimeZone{Id: "W. Europe Standard Time"}},
+	}
+
+	_, err := ProtoDateTimeToTime(d)
+	_require.NotNil(err)
+
+	tm, err := ProtoDateTimeToTimeWithResolver(d, LenientZoneResolver)
+	_require.Nil(err)
+	_require.Equal(time.Date(2024, 2, 14, 8, 0, 0, 0, time.UTC), tm.UTC())
+	_require.Equal("Europe/Berlin", tm.Location().String())
+
+	z, err := ProtoDateTimeToZonedDateTimeWithResolver(d, LenientZoneResolver)
+	_require.Nil(err)
+	_require.Equal("2024-02-14T09:00:00+01:00[Europe/Berlin]", z.String())
+
+	d.TimeOffset = &dtpb.DateTime_TimeZone{TimeZone: &dtpb.TimeZone{Id: "IST"}}
+	_, err = ProtoDateTimeToTimeWithResolver(d, LenientZoneResolver)
+	_require.ErrorIs(err, ErrAmbiguousZone)
+}
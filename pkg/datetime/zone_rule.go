@@ -0,0 +1,211 @@
+package datetime
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+)
+
+// ZoneRule defines a time zone by its standard time and its yearly DST
+// transitions, like the rules of the IANA time zone database, such as for
+// testing zones that don't exist.
+type ZoneRule struct {
+	// StdName is the abbreviation of the standard time, such as "CET"
+	StdName string
+	// StdOffset is the offset of the standard time, east of UTC
+	StdOffset time.Duration
+	// DSTName is the abbreviation of DST, such as "CEST", or "" for a zone
+	// without DST
+	DSTName string
+	// DSTOffset is the offset of DST, east of UTC, StdOffset plus an hour if 0
+	DSTOffset time.Duration
+	// DSTStart and DSTEnd are when DST starts and ends each year
+	DSTStart ZoneRuleDate
+	DSTEnd   ZoneRuleDate
+}
+
+// ZoneRuleDate is a yearly transition of a ZoneRule, on a weekday of a week
+// of a month, such as the last Sunday of March at 02:00.
+type ZoneRuleDate struct {
+	Month time.Month
+	// Week is the week of the month, 1 to 4, or 5 for the last week
+	Week    int
+	Weekday time.Weekday
+	// At is the wall clock time of the transition, in the offset before it,
+	// such as 2 * time.Hour
+	At time.Duration
+}
+
+// String returns the rule as a POSIX TZ string, such as
+// "CET-1CEST,M3.5.0,M10.5.0/3".
+func (r ZoneRule) String() string {
+	var b strings.Builder
+
+	b.WriteString(posixZoneName(r.StdName))
+	b.WriteString(posixOffset(-r.StdOffset))
+	if r.DSTName == "" {
+		return b.String()
+	}
+
+	b.WriteString(posixZoneName(r.DSTName))
+	if dstOffset := r.dstOffset(); dstOffset != r.StdOffset+time.Hour {
+		b.WriteString(posixOffset(-dstOffset))
+	}
+	for _, date := range []ZoneRuleDate{r.DSTStart, r.DSTEnd} {
+		fmt.Fprintf(&b, ",M%d.%d.%d", int(date.Month), date.Week, int(date.Weekday))
+		if date.At != 2*time.Hour {
+			b.WriteString("/" + posixOffset(date.At))
+		}
+	}
+	return b.String()
+}
+
+func (r ZoneRule) dstOffset() time.Duration {
+	if r.DSTOffset == 0 {
+		return r.StdOffset + time.Hour
+	}
+	return r.DSTOffset
+}
+
+func (r ZoneRule) validate() error {
+	names := []string{r.StdName}
+	if r.DSTName != "" {
+		names = append(names, r.DSTName)
+	}
+	for _, name := range names {
+		if len(name) < 3 || strings.ContainsAny(name, "<>,") {
+			return fmt.Errorf("%w: zone abbreviation %q must be at least 3 characters", ErrInvalidValue, name)
+		}
+	}
+	for _, offset := range []time.Duration{r.StdOffset, r.dstOffset()} {
+		if offset <= -25*time.Hour || offset >= 26*time.Hour || offset%time.Second != 0 {
+			return fmt.Errorf("%w: zone offset %s is out of range", ErrInvalidValue, offset)
+		}
+	}
+	if r.DSTName == "" {
+		return nil
+	}
+	for _, date := range []ZoneRuleDate{r.DSTStart, r.DSTEnd} {
+		if date.Month < time.January || date.Month > time.December ||
+			date.Week < 1 || date.Week > 5 ||
+			date.Weekday < time.Sunday || date.Weekday > time.Saturday ||
+			date.At <= -168*time.Hour || date.At >= 168*time.Hour || date.At%time.Second != 0 {
+			return fmt.Errorf("%w: invalid DST transition %+v", ErrInvalidValue, date)
+		}
+	}
+	return nil
+}
+
+// posixZoneName quotes name with <> unless it is only letters.
+func posixZoneName(name string) string {
+	for _, c := range name {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return "<" + name + ">"
+		}
+	}
+	return name
+}
+
+// posixOffset formats d as [-]h[:mm[:ss]].
+func posixOffset(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	hours, minutes, seconds := int(d/time.Hour), int(d/time.Minute%60), int(d/time.Second%60)
+	switch {
+	case seconds != 0:
+		return fmt.Sprintf("%s%d:%02d:%02d", sign, hours, minutes, seconds)
+	case minutes != 0:
+		return fmt.Sprintf("%s%d:%02d", sign, hours, minutes)
+	default:
+		return fmt.Sprintf("%s%d", sign, hours)
+	}
+}
+
+// tzifFromRule returns TZif data without transitions, where the POSIX TZ
+// footer is used for all times.
+func tzifFromRule(r ZoneRule) []byte {
+	return tzifData(r.StdName, r.StdOffset, r.String())
+}
+
+// tzifData returns TZif data with the standard time as the only local time
+// type and the POSIX TZ string footer.
+func tzifData(stdName string, stdOffset time.Duration, footer string) []byte {
+	abbreviation := stdName + "\x00"
+
+	// A version 2 header and data block with 32-bit times, then the same with
+	// 64-bit times, without any transitions they are the same
+	var block []byte
+	block = append(block, "TZif2"...)
+	block = append(block, make([]byte, 15)...)
+	for _, count := range []uint32{0, 0, 0, 0, 1, uint32(len(abbreviation))} { // isut, isstd, leap, time, type, char
+		block = binary.BigEndian.AppendUint32(block, count)
+	}
+	block = binary.BigEndian.AppendUint32(block, uint32(int32(stdOffset/time.Second)))
+	block = append(block, 0, 0) // isdst, abbreviation index
+	block = append(block, abbreviation...)
+
+	data := append(append([]byte(nil), block...), block...)
+	return append(data, "\n"+footer+"\n"...)
+}
+
+// zoneRules holds the zones defined with DefineZone, it is guarded by
+// zoneSourcesMu.
+type zoneRules map[string][]byte
+
+var customZones = zoneRules{}
+
+func (z zoneRules) ZoneData(name string) ([]byte, error) {
+	if data, ok := z[name]; ok {
+		return data, nil
+	}
+	return nil, fs.ErrNotExist
+}
+
+func (z zoneRules) Version() string {
+	return ""
+}
+
+func (z zoneRules) reset() {
+	for name := range z {
+		delete(z, name)
+	}
+}
+
+// DefineZone defines the zone name with the rule, so that LoadLocation, and
+// so all the converters of this package, returns it instead of any zone with
+// the same name.
+func DefineZone(name string, rule ZoneRule) error {
+	if name == "" || name == "UTC" || name == "Local" {
+		return fmt.Errorf("%w: zone name %q is reserved", ErrInvalidValue, name)
+	}
+	if err := rule.validate(); err != nil {
+		return err
+	}
+
+	zoneSourcesMu.Lock()
+	defer zoneSourcesMu.Unlock()
+
+	customZones[name] = tzifFromRule(rule)
+	locationCache.Delete(name)
+	return nil
+}
+
+// DefineFixedZone defines the zone name with a fixed offset east of UTC and
+// no DST, like time.FixedZone but loadable by name. The abbreviation is the
+// offset, like in the IANA time zone database, such as "+0530".
+func DefineFixedZone(name string, offset time.Duration) error {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+	}
+	abs := offset.Abs()
+	abbreviation := fmt.Sprintf("%s%02d", sign, int(abs/time.Hour))
+	if minutes := int(abs / time.Minute % 60); minutes != 0 {
+		abbreviation += fmt.Sprintf("%02d", minutes)
+	}
+	return DefineZone(name, ZoneRule{StdName: abbreviation, StdOffset: offset})
+}
@@ -0,0 +1,100 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The rules of Europe/Stockholm since 1996
+var stockholmRule = ZoneRule{
+	StdName:   "CET",
+	StdOffset: time.Hour,
+	DSTName:   "CEST",
+	DSTStart:  ZoneRuleDate{Month: time.March, Week: 5, Weekday: time.Sunday, At: 2 * time.Hour},
+	DSTEnd:    ZoneRuleDate{Month: time.October, Week: 5, Weekday: time.Sunday, At: 3 * time.Hour},
+}
+
+func Test_ZoneRule_String(t *testing.T) {
+	require.Equal(t, "CET-1CEST,M3.5.0,M10.5.0/3", stockholmRule.String())
+	require.Equal(t, "<+0530>-5:30", ZoneRule{StdName: "+0530", StdOffset: 5*time.Hour + 30*time.Minute}.String())
+	require.Equal(t, "EST5EDT,M3.2.0,M11.1.0", ZoneRule{
+		StdName:   "EST",
+		StdOffset: -5 * time.Hour,
+		DSTName:   "EDT",
+		DSTStart:  ZoneRuleDate{Month: time.March, Week: 2, Weekday: time.Sunday, At: 2 * time.Hour},
+		DSTEnd:    ZoneRuleDate{Month: time.November, Week: 1, Weekday: time.Sunday, At: 2 * time.Hour},
+	}.String())
+}
+
+func Test_DefineZone(t *testing.T) {
+	t.Cleanup(ResetZoneSources)
+	_require := require.New(t)
+
+	_require.Nil(DefineZone("Test/Stockholm", stockholmRule))
+	custom, err := LoadLocation("Test/Stockholm")
+	_require.Nil(err)
+	_require.Equal("Test/Stockholm", custom.String())
+
+	// Same as the IANA zone, every 6 hours for years
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	_require.Nil(err)
+	for tm := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC); tm.Year() < 2040; tm = tm.Add(6 * time.Hour) {
+		name, offset := tm.In(custom).Zone()
+		expectedName, expectedOffset := tm.In(stockholm).Zone()
+		_require.Equal(expectedOffset, offset, tm)
+		_require.Equal(expectedName, name, tm)
+	}
+
+	// And at the transitions
+	_require.Equal(ZoneTransitions(stockholm, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))[0].At.UTC(),
+		ZoneTransitions(custom, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))[0].At.UTC())
+
+	// The proto converters use it
+	z, err := LoadZonedDateTime(time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC), "Test/Stockholm")
+	_require.Nil(err)
+	_require.Equal("2024-07-01T12:00:00+02:00[Test/Stockholm]", z.String())
+
+	// Redefining replaces the cached location
+	_require.Nil(DefineFixedZone("Test/Stockholm", 5*time.Hour+30*time.Minute))
+	custom, err = LoadLocation("Test/Stockholm")
+	_require.Nil(err)
+	name, offset := time.Date(2024, 7, 1, 10, 0, 0, 0, custom).Zone()
+	_require.Equal("+0530", name)
+	_require.Equal(19800, offset)
+
+	_require.Nil(DefineFixedZone("Test/Minus3", -3*time.Hour))
+	custom, err = LoadLocation("Test/Minus3")
+	_require.Nil(err)
+	name, offset = time.Date(2024, 7, 1, 10, 0, 0, 0, custom).Zone()
+	_require.Equal("-03", name)
+	_require.Equal(-3*3600, offset)
+
+	// Defined zones win over the IANA zones
+	_require.Nil(DefineFixedZone("Europe/Stockholm", 0))
+	l, err := LoadLocation("Europe/Stockholm")
+	_require.Nil(err)
+	_, offset = time.Date(2024, 7, 1, 10, 0, 0, 0, l).Zone()
+	_require.Equal(0, offset)
+
+	ResetZoneSources()
+	l, err = LoadLocation("Europe/Stockholm")
+	_require.Nil(err)
+	_, offset = time.Date(2024, 7, 1, 10, 0, 0, 0, l).Zone()
+	_require.Equal(7200, offset)
+	_, err = LoadLocation("Test/Minus3")
+	_require.NotNil(err)
+}
+
+func Test_DefineZone_Invalid(t *testing.T) {
+	t.Cleanup(ResetZoneSources)
+
+	require.ErrorIs(t, DefineZone("UTC", stockholmRule), ErrInvalidValue)
+	require.ErrorIs(t, DefineZone("Test/Zone", ZoneRule{StdName: "X", StdOffset: time.Hour}), ErrInvalidValue)
+	require.ErrorIs(t, DefineZone("Test/Zone", ZoneRule{StdName: "XYZ", StdOffset: 30 * time.Hour}), ErrInvalidValue)
+
+	rule := stockholmRule
+	rule.DSTEnd.Week = 6
+	require.ErrorIs(t, DefineZone("Test/Zone", rule), ErrInvalidValue)
+}
@@ -0,0 +1,190 @@
+package datetime
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ZoneSource provides the TZif data of time zones, such as a newer release of
+// the IANA time zone database than the one bundled with Go.
+type ZoneSource interface {
+	// ZoneData returns the TZif data of the zone name, or an error wrapping
+	// fs.ErrNotExist if the source has no such zone
+	ZoneData(name string) ([]byte, error)
+	// Version returns the tzdata version of the source, such as "2024a", or ""
+	// if unknown
+	Version() string
+}
+
+var (
+	// The registered sources, the last registered first
+	zoneSources   []ZoneSource
+	zoneSourcesMu sync.RWMutex
+)
+
+// RegisterZoneSource makes LoadLocation, and so all the converters of this
+// package, load zones from src before the registered sources and the zone
+// data of Go. Such as to ship a DST rule change before upgrading Go:
+//
+//	//go:embed zoneinfo.zip
+//	var zoneinfo []byte
+//
+//	src, err := datetime.NewZipZoneSource(zoneinfo)
+//	datetime.RegisterZoneSource(src)
+//
+// Locations loaded before are not affected.
+func RegisterZoneSource(src ZoneSource) {
+	zoneSourcesMu.Lock()
+	defer zoneSourcesMu.Unlock()
+
+	zoneSources = append([]ZoneSource{src}, zoneSources...)
+	clearLocationCache()
+}
+
+// ResetZoneSources removes the registered sources and the zones defined with
+// DefineZone, so that zones are loaded from the zone data of Go again.
+func ResetZoneSources() {
+	zoneSourcesMu.Lock()
+	defer zoneSourcesMu.Unlock()
+
+	zoneSources = nil
+	customZones.reset()
+	clearLocationCache()
+}
+
+// loadFromZoneSources returns the location of the zone name from the defined
+// zones or the first registered source that has it, and false if none has it.
+// The caller must hold zoneSourcesMu.
+func loadFromZoneSources(name string) (*time.Location, bool, error) {
+	for _, src := range append([]ZoneSource{customZones}, zoneSources...) {
+		data, err := src.ZoneData(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		l, err := time.LoadLocationFromTZData(name, data)
+		if err != nil {
+			return nil, false, fmt.Errorf("%w: invalid TZif data of %q: %v", ErrInvalidValue, name, err)
+		}
+		return l, true, nil
+	}
+	return nil, false, nil
+}
+
+// TZDataVersion returns the version of the time zone data used by
+// LoadLocation, such as "2024a". It is the version of the last registered
+// source, otherwise of the zone data that Go loads from $ZONEINFO or the host,
+// and "" when unknown, such as for the zone data embedded by time/tzdata.
+func TZDataVersion() string {
+	zoneSourcesMu.RLock()
+	defer zoneSourcesMu.RUnlock()
+
+	if len(zoneSources) > 0 {
+		return zoneSources[0].Version()
+	}
+
+	// The locations Go loads from, before the embedded zone data
+	if zoneinfo := os.Getenv("ZONEINFO"); zoneinfo != "" {
+		if data, err := os.ReadFile(zoneinfo); err == nil {
+			if src, err := NewZipZoneSource(data); err == nil {
+				return src.Version()
+			}
+		}
+		return NewDirZoneSource(zoneinfo).Version()
+	}
+	if runtime.GOOS != "windows" {
+		for _, dir := range []string{"/usr/share/zoneinfo", "/usr/share/lib/zoneinfo", "/usr/lib/locale/TZ", "/etc/zoneinfo"} {
+			if _, err := os.Stat(dir); err == nil {
+				return NewDirZoneSource(dir).Version()
+			}
+		}
+	}
+	return ""
+}
+
+type fsZoneSource struct {
+	fsys fs.FS
+}
+
+// NewFSZoneSource returns a source of the zone files of fsys, laid out like
+// /usr/share/zoneinfo with a file per zone, such as "Europe/Stockholm".
+func NewFSZoneSource(fsys fs.FS) ZoneSource {
+	return &fsZoneSource{fsys: fsys}
+}
+
+// NewDirZoneSource returns a source of the zone files in the directory dir,
+// such as "/usr/share/zoneinfo".
+func NewDirZoneSource(dir string) ZoneSource {
+	return NewFSZoneSource(os.DirFS(dir))
+}
+
+// NewZipZoneSource returns a source of the zone files in a zip archive, such
+// as the lib/time/zoneinfo.zip of Go.
+func NewZipZoneSource(data []byte) (ZoneSource, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid zoneinfo zip: %v", ErrInvalidValue, err)
+	}
+	return NewFSZoneSource(r), nil
+}
+
+func (s *fsZoneSource) ZoneData(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, fs.ErrNotExist
+	}
+	return fs.ReadFile(s.fsys, name)
+}
+
+// Version reads the "+VERSION" file of the zic output, or the version
+// comment of the "tzdata.zi" file.
+func (s *fsZoneSource) Version() string {
+	if data, err := fs.ReadFile(s.fsys, "+VERSION"); err == nil {
+		return strings.TrimSpace(string(data))
+	}
+	f, err := s.fsys.Open("tzdata.zi")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	if scanner.Scan() {
+		if version, ok := strings.CutPrefix(scanner.Text(), "# version "); ok {
+			return strings.TrimSpace(version)
+		}
+	}
+	return ""
+}
+
+type tzifZoneSource struct {
+	name    string
+	data    []byte
+	version string
+}
+
+// NewTZifZoneSource returns a source of the single zone name, with the TZif
+// data of a zone file, such as "/usr/share/zoneinfo/Europe/Stockholm".
+func NewTZifZoneSource(name string, data []byte, version string) ZoneSource {
+	return &tzifZoneSource{name: name, data: data, version: version}
+}
+
+func (s *tzifZoneSource) ZoneData(name string) ([]byte, error) {
+	if name != s.name {
+		return nil, fs.ErrNotExist
+	}
+	return s.data, nil
+}
+
+func (s *tzifZoneSource) Version() string {
+	return s.version
+}
@@ -0,0 +1,88 @@
+package datetime
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func offsetIn(t *testing.T, name string) int {
+	l, err := LoadLocation(name)
+	require.Nil(t, err)
+	_, offset := time.Date(2024, 7, 1, 12, 0, 0, 0, l).Zone()
+	return offset
+}
+
+func Test_RegisterZoneSource_Dir(t *testing.T) {
+	t.Cleanup(ResetZoneSources)
+	_require := require.New(t)
+
+	// A zoneinfo directory where Stockholm has no DST anymore
+	dir := t.TempDir()
+	_require.Nil(os.MkdirAll(filepath.Join(dir, "Europe"), 0o755))
+	_require.Nil(os.WriteFile(filepath.Join(dir, "Europe", "Stockholm"), tzifFromRule(ZoneRule{StdName: "CET", StdOffset: time.Hour}), 0o644))
+	_require.Nil(os.WriteFile(filepath.Join(dir, "+VERSION"), []byte("2099a\n"), 0o644))
+
+	_require.Equal(7200, offsetIn(t, "Europe/Stockholm"))
+
+	RegisterZoneSource(NewDirZoneSource(dir))
+	_require.Equal("2099a", TZDataVersion())
+	_require.Equal(3600, offsetIn(t, "Europe/Stockholm"))
+
+	// Other zones are loaded from the zone data of Go
+	_require.Equal(3*3600, offsetIn(t, "Europe/Helsinki"))
+	_, err := LoadLocation("../../etc/passwd")
+	_require.NotNil(err)
+}
+
+func Test_RegisterZoneSource_Zip(t *testing.T) {
+	t.Cleanup(ResetZoneSources)
+	_require := require.New(t)
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, data := range map[string][]byte{
+		"Europe/Stockholm": tzifFromRule(ZoneRule{StdName: "CET", StdOffset: time.Hour}),
+		"Europe/Broken":    []byte("not TZif"),
+		"tzdata.zi":        []byte("# version 2098b\n# Zone rules\n"),
+	} {
+		f, err := w.Create(name)
+		_require.Nil(err)
+		_, err = f.Write(data)
+		_require.Nil(err)
+	}
+	_require.Nil(w.Close())
+
+	src, err := NewZipZoneSource(buf.Bytes())
+	_require.Nil(err)
+	RegisterZoneSource(src)
+	_require.Equal("2098b", TZDataVersion())
+	_require.Equal(3600, offsetIn(t, "Europe/Stockholm"))
+
+	_, err = LoadLocation("Europe/Broken")
+	_require.ErrorIs(err, ErrInvalidValue)
+
+	_, err = NewZipZoneSource([]byte("not a zip"))
+	_require.ErrorIs(err, ErrInvalidValue)
+}
+
+func Test_RegisterZoneSource_TZif(t *testing.T) {
+	t.Cleanup(ResetZoneSources)
+	_require := require.New(t)
+
+	RegisterZoneSource(NewTZifZoneSource("Europe/Stockholm", tzifFromRule(ZoneRule{StdName: "CET", StdOffset: time.Hour}), "2097c"))
+	_require.Equal(3600, offsetIn(t, "Europe/Stockholm"))
+
+	// The last registered source wins
+	RegisterZoneSource(NewTZifZoneSource("Europe/Stockholm", tzifFromRule(ZoneRule{StdName: "EET", StdOffset: 2 * time.Hour}), "2097d"))
+	_require.Equal("2097d", TZDataVersion())
+	_require.Equal(7200, offsetIn(t, "Europe/Stockholm"))
+	l, _ := LoadLocation("Europe/Stockholm")
+	name, _ := time.Date(2024, 1, 1, 0, 0, 0, 0, l).Zone()
+	_require.Equal("EET", name)
+}
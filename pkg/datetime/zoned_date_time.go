@@ -0,0 +1,182 @@
+package datetime
+
+import (
+	"fmt"
+	"time"
+
+	dtpb "google.golang.org/genproto/googleapis/type/datetime"
+	durpb "google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ZonedDateTime is an instant together with the location it shall be
+// presented in, like the SQL "timestamptz" type paired with a clinic's
+// time zone.
+//
+// Unlike a Time, a ZonedDateTime keeps the IANA zone ID ("Europe/Stockholm")
+// when it is serialized, using the RFC 9557 (IXDTF) format
+// "2024-02-14T09:00:00+01:00[Europe/Stockholm]" for strings and
+// google.type.TimeZone for protos.
+type ZonedDateTime struct {
+	t    time.Time
+	loc  *time.Location
+	zone string
+}
+
+// NewZonedDateTime returns the instant t presented in the location l. The zone
+// ID is the name of l when it is an IANA zone, like the locations returned by
+// time.LoadLocation, otherwise only the UTC offset is kept.
+func NewZonedDateTime(t time.Time, l *time.Location) ZonedDateTime {
+	return ZonedDateTime{t: t.In(l), loc: l, zone: zoneIDOf(l)}
+}
+
+// LoadZonedDateTime returns the instant t presented in the IANA zone zoneID.
+func LoadZonedDateTime(t time.Time, zoneID string) (ZonedDateTime, error) {
+	l, err := LoadLocation(zoneID)
+	if err != nil {
+		return ZonedDateTime{}, fmt.Errorf("%w: unknown time zone %q", ErrInvalidValue, zoneID)
+	}
+	return ZonedDateTime{t: t.In(l), loc: l, zone: zoneID}, nil
+}
+
+// ParseZonedDateTime parses an RFC 9557 (IXDTF) string such as
+// "2024-02-14T09:00:00+01:00[Europe/Stockholm]". The offset must match the
+// offset of the zone at that instant. Without a bracketed zone the result
+// has a fixed UTC offset. See ParseIXDTF for the full syntax.
+func ParseZonedDateTime(s string) (ZonedDateTime, error) {
+	v, err := ParseIXDTF(s)
+	if err != nil {
+		return ZonedDateTime{}, err
+	}
+	return v.ZonedDateTime(), nil
+}
+
+// Time returns the instant as a Time in the location of z.
+func (z ZonedDateTime) Time() time.Time {
+	return z.t
+}
+
+// Location returns the location of z, UTC if none is set.
+func (z ZonedDateTime) Location() *time.Location {
+	if z.loc == nil {
+		return time.UTC
+	}
+	return z.loc
+}
+
+// ZoneID returns the IANA zone ID of z, or "" if z only has a UTC offset.
+func (z ZonedDateTime) ZoneID() string {
+	return z.zone
+}
+
+// Offset returns the UTC offset of z at its instant.
+func (z ZonedDateTime) Offset() time.Duration {
+	_, offset := z.t.Zone()
+	return time.Duration(offset) * time.Second
+}
+
+// IsZero reports whether z represents the zero time instant.
+func (z ZonedDateTime) IsZero() bool {
+	return z.t.IsZero()
+}
+
+// Equal reports whether z and other represent the same instant in the same
+// zone.
+func (z ZonedDateTime) Equal(other ZonedDateTime) bool {
+	return z.t.Equal(other.t) && z.zone == other.zone && z.Offset() == other.Offset()
+}
+
+// String returns z formatted as an RFC 9557 (IXDTF) string, an ISO8601 date
+// time with its UTC offset followed by the bracketed zone ID if z has one.
+func (z ZonedDateTime) String() string {
+	return FormatIXDTF(IXDTF{Time: z.t, Zone: z.zone})
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (z ZonedDateTime) MarshalText() ([]byte, error) {
+	return []byte(z.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (z *ZonedDateTime) UnmarshalText(data []byte) error {
+	parsed, err := ParseZonedDateTime(string(data))
+	if err != nil {
+		return err
+	}
+	*z = parsed
+	return nil
+}
+
+// ZonedDateTimeToProtoDateTime returns a new google.type.DateTime with the wall
+// clock time of z, with the zone ID in TimeZone or, when z only has a UTC
+// offset, the offset in UtcOffset.
+func ZonedDateTimeToProtoDateTime(z ZonedDateTime) *dtpb.DateTime {
+	dt := &dtpb.DateTime{
+		Year:    int32(z.t.Year()),
+		Month:   int32(z.t.Month()),
+		Day:     int32(z.t.Day()),
+		Hours:   int32(z.t.Hour()),
+		Minutes: int32(z.t.Minute()),
+		Seconds: int32(z.t.Second()),
+		Nanos:   int32(z.t.Nanosecond()),
+	}
+
+	if z.zone != "" {
+		dt.TimeOffset = &dtpb.DateTime_TimeZone{
+			TimeZone: &dtpb.TimeZone{Id: z.zone},
+		}
+	} else {
+		dt.TimeOffset = &dtpb.DateTime_UtcOffset{
+			UtcOffset: &durpb.Duration{Seconds: int64(z.Offset() / time.Second)},
+		}
+	}
+
+	return dt
+}
+
+// ProtoDateTimeToZonedDateTime returns a new ZonedDateTime based on the
+// google.type.DateTime, keeping the zone ID of its TimeZone.
+//
+// A wall clock time that occurs twice in the zone, when the clocks are turned
+// back, is ambiguous since the proto has no UTC offset, and resolves to the
+// instant chosen by time.Date.
+func ProtoDateTimeToZonedDateTime(d *dtpb.DateTime) (ZonedDateTime, error) {
+	t, err := ProtoDateTimeToTime(d)
+	if err != nil {
+		return ZonedDateTime{}, err
+	}
+	if zoneID := d.GetTimeZone().GetId(); zoneID != "" {
+		return ZonedDateTime{t: t, loc: t.Location(), zone: zoneID}, nil
+	}
+	return ZonedDateTime{t: t, loc: t.Location()}, nil
+}
+
+// ProtoDateTimeToZonedDateTimeWithResolver is like
+// ProtoDateTimeToZonedDateTime, but resolves the TimeZone with r. The zone ID
+// is the IANA zone ID it was resolved to, such as "Europe/Berlin" for
+// "W. Europe Standard Time".
+func ProtoDateTimeToZonedDateTimeWithResolver(d *dtpb.DateTime, r ZoneResolver) (ZonedDateTime, error) {
+	t, err := ProtoDateTimeToTimeWithResolver(d, r)
+	if err != nil {
+		return ZonedDateTime{}, err
+	}
+	if d.GetTimeZone().GetId() != "" {
+		return ZonedDateTime{t: t, loc: t.Location(), zone: t.Location().String()}, nil
+	}
+	return ZonedDateTime{t: t, loc: t.Location()}, nil
+}
+
+// zoneIDOf returns the IANA zone ID of l, or "" if l is a fixed offset or the
+// system's local time zone.
+func zoneIDOf(l *time.Location) string {
+	name := l.String()
+	switch name {
+	case "", "Local":
+		return ""
+	case "UTC":
+		return name
+	}
+	if _, err := LoadLocation(name); err != nil {
+		return ""
+	}
+	return name
+}
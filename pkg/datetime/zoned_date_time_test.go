@@ -0,0 +1,116 @@
+package datetime
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	dtpb "google.golang.org/genproto/googleapis/type/datetime"
+	durpb "google.golang.org/protobuf/types/known/durationpb"
+)
+
+func Test_ParseZonedDateTime(t *testing.T) {
+	for _, test := range []struct {
+		from, zoneID string
+		offset       time.Duration
+	}{
+		{"2024-02-14T09:00:00+01:00[Europe/Stockholm]", "Europe/Stockholm", time.Hour},
+		{"2024-07-14T09:00:00+02:00[Europe/Stockholm]", "Europe/Stockholm", 2 * time.Hour},
+		{"2024-07-14T09:00:00-04:00[America/New_York]", "America/New_York", -4 * time.Hour},
+		{"2024-02-14T08:00:00Z[UTC]", "UTC", 0},
+		{"2024-02-14T09:00:00+01:00", "", time.Hour},
+		{"2024-02-14T08:00:00Z", "", 0},
+	} {
+		t.Run(test.from, func(t *testing.T) {
+			z, err := ParseZonedDateTime(test.from)
+			require.Nil(t, err)
+			require.Equal(t, test.zoneID, z.ZoneID())
+			require.Equal(t, test.offset, z.Offset())
+			require.Equal(t, test.from, z.String())
+		})
+	}
+
+	z, err := ParseZonedDateTime("2024-02-14T09:00:00+01:00[!Europe/Stockholm]")
+	require.Nil(t, err)
+	require.Equal(t, "Europe/Stockholm", z.ZoneID())
+
+	for _, from := range []string{
+		"2024-02-14T09:00:00+02:00[Europe/Stockholm]", // offset not used by the zone at that instant
+		"2024-02-14T09:00:00+01:00[Fake/Ness]",
+		"2024-02-14T09:00:00+01:00[Europe/Stockholm",
+		"2024-02-14X09:00:00+01:00[Europe/Stockholm]",
+	} {
+		_, err := ParseZonedDateTime(from)
+		require.ErrorIsf(t, err, ErrInvalidValue, from)
+	}
+}
+
+func Test_ZonedDateTime_RoundTrip(t *testing.T) {
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	require.Nil(t, err)
+
+	z := NewZonedDateTime(time.Date(2024, 2, 14, 8, 0, 0, 500, time.UTC), stockholm)
+	require.Equal(t, "Europe/Stockholm", z.ZoneID())
+
+	// A plain ISO8601 string loses the zone...
+	parsed, err := ISO8601StringToTime(TimeToISO8601DateTimeString(z.Time()))
+	require.Nil(t, err)
+	require.Equal(t, "", NewZonedDateTime(parsed, parsed.Location()).ZoneID())
+
+	// ...but the IXDTF string keeps it
+	data, err := json.Marshal(z)
+	require.Nil(t, err)
+	require.Equal(t, `"2024-02-14T09:00:00.0000005+01:00[Europe/Stockholm]"`, string(data))
+
+	var unmarshalled ZonedDateTime
+	require.Nil(t, json.Unmarshal(data, &unmarshalled))
+	require.True(t, z.Equal(unmarshalled))
+	require.Equal(t, z.ZoneID(), unmarshalled.ZoneID())
+
+	// ...and so does the proto
+	proto := ZonedDateTimeToProtoDateTime(z)
+	require.Equal(t, "Europe/Stockholm", proto.GetTimeZone().GetId())
+	require.Equal(t, int32(9), proto.GetHours())
+	require.Equal(t, int32(500), proto.GetNanos())
+
+	fromProto, err := ProtoDateTimeToZonedDateTime(proto)
+	require.Nil(t, err)
+	require.True(t, z.Equal(fromProto))
+}
+
+func Test_ZonedDateTime_ProtoUtcOffset(t *testing.T) {
+	z, err := ParseZonedDateTime("2024-02-14T09:00:00-05:30")
+	require.Nil(t, err)
+
+	proto := ZonedDateTimeToProtoDateTime(z)
+	require.Nil(t, proto.GetTimeZone())
+	require.Equal(t, int64(-5*3600-30*60), proto.GetUtcOffset().GetSeconds())
+
+	fromProto, err := ProtoDateTimeToZonedDateTime(proto)
+	require.Nil(t, err)
+	require.Equal(t, "", fromProto.ZoneID())
+	require.True(t, z.Time().Equal(fromProto.Time()))
+
+	_, err = ProtoDateTimeToZonedDateTime(&dtpb.DateTime{
+		Year: 2024, Month: 2, Day: 14,
+		TimeOffset: &dtpb.DateTime_TimeZone{TimeZone: &dtpb.TimeZone{Id: "Fake/Ness"}},
+	})
+	require.NotNil(t, err)
+
+	fromProto, err = ProtoDateTimeToZonedDateTime(&dtpb.DateTime{
+		Year: 2024, Month: 2, Day: 14,
+		TimeOffset: &dtpb.DateTime_UtcOffset{UtcOffset: &durpb.Duration{Seconds: 3600}},
+	})
+	require.Nil(t, err)
+	require.Equal(t, "2024-02-14T00:00:00+01:00", fromProto.String())
+}
+
+func Test_LoadZonedDateTime(t *testing.T) {
+	z, err := LoadZonedDateTime(time.Date(2024, 2, 14, 8, 0, 0, 0, time.UTC), "America/New_York")
+	require.Nil(t, err)
+	require.Equal(t, "2024-02-14T03:00:00-05:00[America/New_York]", z.String())
+
+	_, err = LoadZonedDateTime(time.Now(), "Fake/Ness")
+	require.ErrorIs(t, err, ErrInvalidValue)
+}
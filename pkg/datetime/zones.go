@@ -0,0 +1,170 @@
+package datetime
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The zone metadata is embedded, from the zone.tab and the links of the IANA
+// time zone database, since time/tzdata can load zones but not list them.
+//
+//go:embed zones/zones.json
+var zonesFile []byte
+
+type zoneData struct {
+	Version string `json:"version"`
+	// Zones are sorted by ID
+	Zones []struct {
+		ID      string `json:"id"`
+		Country string `json:"country"` // ISO 3166 code, "" for UTC
+	} `json:"zones"`
+	// Aliases are the deprecated or backward compatible zone IDs, with the
+	// canonical zone ID they link to
+	Aliases map[string]string `json:"aliases"`
+}
+
+var (
+	zones     *zoneData
+	zonesOnce sync.Once
+)
+
+func loadZones() *zoneData {
+	zonesOnce.Do(func() {
+		zones = &zoneData{}
+		if err := json.Unmarshal(zonesFile, zones); err != nil {
+			panic(fmt.Sprintf("zones/zones.json: %v", err))
+		}
+	})
+	return zones
+}
+
+// ListZones returns the sorted IDs of the canonical IANA time zones, such as
+// "Europe/Stockholm", to pick from. Aliases such as "Europe/Kiev" are not
+// included.
+func ListZones() []string {
+	data := loadZones()
+	ids := make([]string, 0, len(data.Zones))
+	for _, zone := range data.Zones {
+		ids = append(ids, zone.ID)
+	}
+	return ids
+}
+
+// SearchZones returns the sorted IDs of the canonical zones containing query,
+// ignoring case and with spaces matching underscores. Such as "new york" or
+// "stockholm".
+func SearchZones(query string) []string {
+	query = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(query), " ", "_"))
+	if query == "" {
+		return nil
+	}
+
+	var ids []string
+	for _, zone := range loadZones().Zones {
+		if strings.Contains(strings.ToLower(zone.ID), query) {
+			ids = append(ids, zone.ID)
+		}
+	}
+	return ids
+}
+
+// ZonesOfCountry returns the sorted IDs of the canonical zones of the country
+// with the ISO 3166 code country, such as "SE".
+func ZonesOfCountry(country string) []string {
+	var ids []string
+	for _, zone := range loadZones().Zones {
+		if zone.Country != "" && strings.EqualFold(zone.Country, country) {
+			ids = append(ids, zone.ID)
+		}
+	}
+	return ids
+}
+
+// CanonicalZone returns the canonical ID of a zone alias, such as
+// "Europe/Kyiv" for "Europe/Kiev", or id itself if it is not an alias.
+func CanonicalZone(id string) string {
+	if canonical, ok := loadZones().Aliases[id]; ok {
+		return canonical
+	}
+	return id
+}
+
+// ValidateZone returns an error wrapping ErrInvalidValue if id is not the ID
+// of an IANA time zone that can be loaded. Unlike time.LoadLocation, "" and
+// "Local" are not accepted since they depend on the host.
+func ValidateZone(id string) error {
+	if id == "" || id == "Local" {
+		return fmt.Errorf("%w: %q is not an IANA time zone", ErrInvalidValue, id)
+	}
+	if _, err := LoadLocation(id); err != nil {
+		return fmt.Errorf("%w: %q is not an IANA time zone: %v", ErrInvalidValue, id, err)
+	}
+	return nil
+}
+
+// ZoneTransition is a change of the UTC offset or the abbreviation of a time
+// zone, such as the start or end of DST.
+type ZoneTransition struct {
+	// At is the first instant with the new offset
+	At           time.Time
+	NameBefore   string
+	OffsetBefore time.Duration
+	NameAfter    string
+	OffsetAfter  time.Duration
+}
+
+// Change returns how much the clocks are turned, positive when they are
+// turned forward and negative when turned back.
+func (t ZoneTransition) Change() time.Duration {
+	return t.OffsetAfter - t.OffsetBefore
+}
+
+// ZoneTransitions returns the transitions of the location l within [from, to),
+// in order. Such as the start and end of DST within a year.
+func ZoneTransitions(l *time.Location, from, to time.Time) []ZoneTransition {
+	var transitions []ZoneTransition
+	t := from
+	for {
+		transition, ok := NextTransition(l, t)
+		if !ok || !transition.At.Before(to) {
+			return transitions
+		}
+		transitions = append(transitions, transition)
+		t = transition.At
+	}
+}
+
+// NextTransition returns the first transition of the location l after t, and
+// false if the location has no more transitions, like UTC.
+func NextTransition(l *time.Location, t time.Time) (ZoneTransition, bool) {
+	if l == nil {
+		l = time.UTC
+	}
+	t = t.In(l)
+	name, offset := t.Zone()
+
+	// Zone periods may end without a change of the offset or the name, such
+	// as when only the DST flag changes, those are skipped
+	for i := 0; i < 1000; i++ {
+		_, end := t.ZoneBounds()
+		if end.IsZero() {
+			return ZoneTransition{}, false
+		}
+		nameAfter, offsetAfter := end.Zone()
+		if nameAfter != name || offsetAfter != offset {
+			return ZoneTransition{
+				At:           end,
+				NameBefore:   name,
+				OffsetBefore: time.Duration(offset) * time.Second,
+				NameAfter:    nameAfter,
+				OffsetAfter:  time.Duration(offsetAfter) * time.Second,
+			}, true
+		}
+		t = end
+	}
+	return ZoneTransition{}, false
+}
@@ -0,0 +1,94 @@
+package datetime
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListZones(t *testing.T) {
+	zones := ListZones()
+	require.True(t, sort.StringsAreSorted(zones))
+	require.Contains(t, zones, "Europe/Stockholm")
+	require.Contains(t, zones, "UTC")
+	require.NotContains(t, zones, "Europe/Kiev")
+
+	// All of them can be loaded from the embedded time zone data
+	for _, zone := range zones {
+		require.Nil(t, ValidateZone(zone), zone)
+	}
+}
+
+func Test_SearchZones(t *testing.T) {
+	require.Equal(t, []string{"America/New_York"}, SearchZones("new york"))
+	require.Equal(t, []string{"Europe/Stockholm"}, SearchZones("STOCKHOLM"))
+	require.Contains(t, SearchZones("europe/"), "Europe/Oslo")
+	require.Nil(t, SearchZones(" "))
+}
+
+func Test_ZonesOfCountry(t *testing.T) {
+	require.Equal(t, []string{"Europe/Stockholm"}, ZonesOfCountry("se"))
+	require.Equal(t, []string{"Europe/Kyiv", "Europe/Simferopol"}, ZonesOfCountry("UA"))
+	require.Nil(t, ZonesOfCountry(""))
+}
+
+func Test_CanonicalZone(t *testing.T) {
+	require.Equal(t, "Europe/Kyiv", CanonicalZone("Europe/Kiev"))
+	require.Equal(t, "Asia/Kolkata", CanonicalZone("Asia/Calcutta"))
+	require.Equal(t, "UTC", CanonicalZone("Etc/UTC"))
+	require.Equal(t, "Europe/Stockholm", CanonicalZone("Europe/Stockholm"))
+	require.Equal(t, "Nowhere/Special", CanonicalZone("Nowhere/Special"))
+}
+
+func Test_ValidateZone(t *testing.T) {
+	require.Nil(t, ValidateZone("Europe/Stockholm"))
+	require.Nil(t, ValidateZone("Europe/Kiev"))
+	require.ErrorIs(t, ValidateZone("Europe/Stockholmm"), ErrInvalidValue)
+	require.ErrorIs(t, ValidateZone(""), ErrInvalidValue)
+	require.ErrorIs(t, ValidateZone("Local"), ErrInvalidValue)
+}
+
+func Test_ZoneTransitions(t *testing.T) {
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	require.Nil(t, err)
+
+	transitions := ZoneTransitions(stockholm, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.Equal(t, []ZoneTransition{
+		{
+			At:           time.Date(2024, 3, 31, 1, 0, 0, 0, time.UTC).In(stockholm),
+			NameBefore:   "CET",
+			OffsetBefore: time.Hour,
+			NameAfter:    "CEST",
+			OffsetAfter:  2 * time.Hour,
+		},
+		{
+			At:           time.Date(2024, 10, 27, 1, 0, 0, 0, time.UTC).In(stockholm),
+			NameBefore:   "CEST",
+			OffsetBefore: 2 * time.Hour,
+			NameAfter:    "CET",
+			OffsetAfter:  time.Hour,
+		},
+	}, transitions)
+	require.Equal(t, time.Hour, transitions[0].Change())
+	require.Equal(t, -time.Hour, transitions[1].Change())
+
+	// "DST changes this Sunday"
+	next, ok := NextTransition(stockholm, time.Date(2024, 3, 27, 12, 0, 0, 0, stockholm))
+	require.True(t, ok)
+	require.Equal(t, NewDate(2024, 3, 31), DateOf(next.At))
+
+	// Far into the future, the transitions follow the DST rules
+	next, ok = NextTransition(stockholm, time.Date(2100, 6, 1, 0, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	require.Equal(t, time.Date(2100, 10, 31, 1, 0, 0, 0, time.UTC), next.At.UTC())
+
+	_, ok = NextTransition(time.UTC, time.Now())
+	require.False(t, ok)
+
+	// No DST in Iceland since 1968
+	reykjavik, err := time.LoadLocation("Atlantic/Reykjavik")
+	require.Nil(t, err)
+	require.Empty(t, ZoneTransitions(reykjavik, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+}